@@ -7,23 +7,37 @@ import (
 	"github.com/aws/aws-sdk-go/service/dynamodb"
 )
 
+// DynamoDB marshaling of ID, IDSet and KeySet follows a single policy: empty marshals as NULL, which omitempty
+// omits, and NULL or a missing attribute unmarshals as empty. DynamoDB rejects empty S values for keys and empty SS
+// values entirely, so an empty id or set is never written as such; blank ids within a set are dropped.
+
+// MarshalDynamoDBAttributeValue marshals the id as S or, if empty, NULL
 func (id ID) MarshalDynamoDBAttributeValue(item *dynamodb.AttributeValue) error {
 	if item == nil {
 		return fmt.Errorf("unable to marshal key: nil AttributeValue")
 	}
 
+	if id == "" {
+		item.NULL = aws.Bool(true)
+		return nil
+	}
+
 	item.S = aws.String(id.String())
 
 	return nil
 }
 
+// UnmarshalDynamoDBAttributeValue unmarshals S or, as an empty id, NULL
 func (id *ID) UnmarshalDynamoDBAttributeValue(item *dynamodb.AttributeValue) error {
-	if item.S == nil {
-		return fmt.Errorf("unable to marshal key: nil string")
+	switch {
+	case item == nil || aws.BoolValue(item.NULL):
+		*id = ""
+	case item.S != nil:
+		*id = ID(aws.StringValue(item.S))
+	default:
+		return fmt.Errorf("unable to unmarshal key: want S or NULL attribute")
 	}
 
-	*id = ID(aws.StringValue(item.S))
-
 	return nil
 }
 
@@ -50,30 +64,12 @@ func NewKeySet(ss ...string) KeySet {
 }
 
 func (kk KeySet) Contains(key ID) bool {
-	for _, k := range kk {
-		if k == key {
-			return true
-		}
-	}
-	return false
+	return IDSet(kk).Contains(key)
 }
 
+// MarshalDynamoDBAttributeValue marshals the distinct keys as SS or, if there are none, NULL
 func (kk KeySet) MarshalDynamoDBAttributeValue(item *dynamodb.AttributeValue) error {
-	var (
-		seen = map[ID]struct{}{}
-		ss   []string
-	)
-	for _, k := range kk {
-		if _, ok := seen[k]; ok {
-			continue
-		}
-		ss = append(ss, k.String())
-		seen[k] = struct{}{}
-	}
-
-	item.SS = aws.StringSlice(ss)
-
-	return nil
+	return IDSet(kk).Trim().Dedupe().MarshalDynamoDBAttributeValue(item)
 }
 
 // Strings exports keys as string slice
@@ -85,12 +81,14 @@ func (kk KeySet) Strings() []string {
 	return ss
 }
 
+// UnmarshalDynamoDBAttributeValue unmarshals SS or, as an empty set, NULL
 func (kk *KeySet) UnmarshalDynamoDBAttributeValue(item *dynamodb.AttributeValue) error {
-	if item.SS == nil {
-		return nil
+	var set IDSet
+	if err := set.UnmarshalDynamoDBAttributeValue(item); err != nil {
+		return err
 	}
 
-	*kk = NewKeySet(aws.StringValueSlice(item.SS)...)
+	*kk = NewKeySet(set.Strings()...)
 
 	return nil
 }
@@ -3,6 +3,8 @@ package frn
 import (
 	"testing"
 
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/dynamodb"
 	"github.com/aws/aws-sdk-go/service/dynamodb/dynamodbattribute"
 	"github.com/tj/assert"
 )
@@ -57,3 +59,114 @@ func TestNewKeySet(t *testing.T) {
 		})
 	}
 }
+
+func TestDynamoDB_EmptyIsNull(t *testing.T) {
+	type Record struct {
+		ID         ID
+		IDPtr      *ID
+		IDSet      IDSet
+		KeySet     KeySet
+		OmitID     ID     `dynamodbav:",omitempty"`
+		OmitIDPtr  *ID    `dynamodbav:",omitempty"`
+		OmitIDSet  IDSet  `dynamodbav:",omitempty"`
+		OmitKeySet KeySet `dynamodbav:",omitempty"`
+	}
+
+	var (
+		id    = ID("fm:crm:project:1")
+		empty = ID("")
+	)
+
+	testCases := map[string]struct {
+		Record   Record
+		Want     Record
+		WantKeys []string // WantKeys holds the attributes that are not NULL
+		WantNull []string
+	}{
+		"empty": {
+			Record:   Record{},
+			Want:     Record{},
+			WantNull: []string{"ID", "IDPtr", "IDSet", "KeySet"},
+		},
+		"empty values": {
+			Record: Record{
+				IDPtr:      &empty,
+				IDSet:      IDSet{},
+				KeySet:     KeySet{},
+				OmitIDPtr:  &empty,
+				OmitIDSet:  IDSet{""},
+				OmitKeySet: KeySet{""},
+			},
+			Want:     Record{},
+			WantNull: []string{"ID", "IDPtr", "IDSet", "KeySet"},
+		},
+		"present": {
+			Record: Record{
+				ID:         id,
+				IDPtr:      &id,
+				IDSet:      IDSet{id},
+				KeySet:     KeySet{id, id},
+				OmitID:     id,
+				OmitIDPtr:  &id,
+				OmitIDSet:  IDSet{id, ""},
+				OmitKeySet: KeySet{id},
+			},
+			Want: Record{
+				ID:         id,
+				IDPtr:      &id,
+				IDSet:      IDSet{id},
+				KeySet:     KeySet{id},
+				OmitID:     id,
+				OmitIDPtr:  &id,
+				OmitIDSet:  IDSet{id},
+				OmitKeySet: KeySet{id},
+			},
+			WantKeys: []string{"ID", "IDPtr", "IDSet", "KeySet", "OmitID", "OmitIDPtr", "OmitIDSet", "OmitKeySet"},
+		},
+	}
+
+	for label, tc := range testCases {
+		t.Run(label, func(t *testing.T) {
+			item, err := dynamodbattribute.MarshalMap(tc.Record)
+			assert.Nil(t, err)
+
+			var keys, nulls []string
+			for k, v := range item {
+				if aws.BoolValue(v.NULL) {
+					nulls = append(nulls, k)
+				} else {
+					keys = append(keys, k)
+				}
+			}
+			assert.ElementsMatch(t, tc.WantKeys, keys)
+			assert.ElementsMatch(t, tc.WantNull, nulls)
+
+			var got Record
+			err = dynamodbattribute.UnmarshalMap(item, &got)
+			assert.Nil(t, err)
+			assert.Equal(t, tc.Want, got)
+		})
+	}
+}
+
+func TestDynamoDB_UnmarshalNull(t *testing.T) {
+	null := &dynamodb.AttributeValue{NULL: aws.Bool(true)}
+
+	id := ID("fm:crm:project:1")
+	assert.Nil(t, dynamodbattribute.Unmarshal(null, &id))
+	assert.Equal(t, ID(""), id)
+
+	set := IDSet{"fm:crm:project:1"}
+	assert.Nil(t, dynamodbattribute.Unmarshal(null, &set))
+	assert.Nil(t, set)
+
+	keys := KeySet{"fm:crm:project:1"}
+	assert.Nil(t, dynamodbattribute.Unmarshal(null, &keys))
+	assert.Nil(t, keys)
+
+	list := &dynamodb.AttributeValue{L: []*dynamodb.AttributeValue{{S: aws.String("fm:crm:project:1")}}}
+	assert.Nil(t, dynamodbattribute.Unmarshal(list, &set))
+	assert.Equal(t, IDSet{"fm:crm:project:1"}, set)
+
+	assert.NotNil(t, dynamodbattribute.Unmarshal(&dynamodb.AttributeValue{N: aws.String("1")}, &id))
+}
@@ -0,0 +1,284 @@
+// Command frn inspects, validates and generates FRNs.
+//
+//	frn parse [--json] [id ...]
+//	frn validate [--pattern project/contract] [id ...]
+//	frn new --ns fm:crm --type project [--count 1]
+//	frn shape [id ...]
+//	frn time [id ...]
+//
+// ids are read from the arguments or, if there are none, from stdin one per line
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/Freemodel-Inc/frn"
+)
+
+const usage = `usage: frn <command> [flags] [id ...]
+
+commands:
+  parse     print the components of each id; --json for json output
+  validate  validate each id against --pattern, which may be repeated, or only that it is valid
+  new       generate --count new ids of --type within --ns e.g. fm:crm
+  shape     print the shape of each id
+  time      print the creation time of each KSUID based id
+
+ids are read from the arguments or, if there are none, from stdin one per line
+`
+
+func main() {
+	os.Exit(run(os.Args[1:], os.Stdin, os.Stdout, os.Stderr))
+}
+
+// run executes the command in args and returns the exit code; 1 if the command failed, 2 if it could not be parsed
+func run(args []string, stdin io.Reader, stdout, stderr io.Writer) int {
+	if len(args) == 0 {
+		fmt.Fprint(stderr, usage)
+		return 2
+	}
+
+	fs := flag.NewFlagSet("frn "+args[0], flag.ContinueOnError)
+	fs.SetOutput(stderr)
+
+	var (
+		cmd     func(ids []string) error
+		readIDs = true
+	)
+	switch args[0] {
+	case "parse":
+		asJSON := fs.Bool("json", false, "print json, one object per line")
+		cmd = func(ids []string) error { return parseIDs(stdout, ids, *asJSON) }
+	case "validate":
+		var patterns stringSlice
+		fs.Var(&patterns, "pattern", "pattern the ids must match e.g. project/contract; may be repeated")
+		cmd = func(ids []string) error { return validateIDs(stdout, stderr, ids, patterns) }
+	case "new":
+		var (
+			ns    = fs.String("ns", "", "namespace of the ids e.g. fm:crm")
+			t     = fs.String("type", "", "type of the ids e.g. project")
+			count = fs.Int("count", 1, "number of ids to generate")
+		)
+		readIDs = false
+		cmd = func([]string) error { return newIDs(stdout, frn.Namespace(*ns), frn.Type(*t), *count) }
+	case "shape":
+		cmd = func(ids []string) error { return shapeIDs(stdout, ids) }
+	case "time":
+		cmd = func(ids []string) error { return timeIDs(stdout, stderr, ids) }
+	case "help", "-h", "--help":
+		fmt.Fprint(stdout, usage)
+		return 0
+	default:
+		fmt.Fprintf(stderr, "frn: unknown command, %v\n\n%v", args[0], usage)
+		return 2
+	}
+
+	if err := fs.Parse(args[1:]); err != nil {
+		return 2
+	}
+
+	ids := fs.Args()
+	if len(ids) == 0 && readIDs {
+		var err error
+		if ids, err = readLines(stdin); err != nil {
+			fmt.Fprintln(stderr, err)
+			return 1
+		}
+	}
+
+	if err := cmd(ids); err != nil {
+		if !errors.Is(err, errFailed) {
+			fmt.Fprintln(stderr, err)
+		}
+		return 1
+	}
+	return 0
+}
+
+// errFailed is returned once the failure of one or more ids has already been reported
+var errFailed = errors.New("one or more ids failed")
+
+// stringSlice is a flag that may be repeated
+type stringSlice []string
+
+func (ss *stringSlice) String() string {
+	return strings.Join(*ss, ",")
+}
+
+func (ss *stringSlice) Set(s string) error {
+	*ss = append(*ss, s)
+	return nil
+}
+
+// readLines returns the non-blank lines of r
+func readLines(r io.Reader) ([]string, error) {
+	var lines []string
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		if line := strings.TrimSpace(scanner.Text()); line != "" {
+			lines = append(lines, line)
+		}
+	}
+	return lines, scanner.Err()
+}
+
+// segment is the json form of frn.Segment
+type segment struct {
+	Type  frn.Type `json:"type"`
+	Value string   `json:"value"`
+}
+
+// components is the json form of frn.Components
+type components struct {
+	ID       frn.ID      `json:"id"`
+	Env      string      `json:"env"`
+	Service  frn.Service `json:"service"`
+	Type     frn.Type    `json:"type"`
+	Value    string      `json:"value"`
+	Children []segment   `json:"children,omitempty"`
+	Path     []string    `json:"path,omitempty"`
+	Shape    string      `json:"shape"`
+}
+
+func parseIDs(w io.Writer, ids []string, asJSON bool) error {
+	var (
+		failed bool
+		enc    = json.NewEncoder(w)
+		tw     = tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+	)
+	for i, s := range ids {
+		c, err := frn.Parse(s)
+		if err != nil {
+			failed = true
+			if asJSON {
+				if err := enc.Encode(struct {
+					ID    string `json:"id"`
+					Error string `json:"error"`
+				}{ID: s, Error: err.Error()}); err != nil {
+					return err
+				}
+			} else {
+				fmt.Fprintf(tw, "id\t%v\nerror\t%v\n", s, err)
+			}
+			continue
+		}
+
+		v := components{
+			ID:      frn.ID(s),
+			Env:     c.Env,
+			Service: c.Service,
+			Type:    c.Type,
+			Value:   c.Value,
+			Path:    c.Path,
+			Shape:   frn.ID(s).Shape(),
+		}
+		for _, child := range c.Children {
+			v.Children = append(v.Children, segment{Type: child.Type, Value: child.Value})
+		}
+
+		if asJSON {
+			if err := enc.Encode(v); err != nil {
+				return err
+			}
+			continue
+		}
+
+		if i > 0 {
+			fmt.Fprintln(tw)
+		}
+		fmt.Fprintf(tw, "id\t%v\n", v.ID)
+		fmt.Fprintf(tw, "env\t%v\n", v.Env)
+		fmt.Fprintf(tw, "service\t%v\n", v.Service)
+		fmt.Fprintf(tw, "type\t%v\n", v.Type)
+		fmt.Fprintf(tw, "value\t%v\n", v.Value)
+		for _, child := range v.Children {
+			fmt.Fprintf(tw, "child\t%v:%v\n", child.Type, child.Value)
+		}
+		if len(v.Path) > 0 {
+			fmt.Fprintf(tw, "path\t%v\n", strings.Join(v.Path, "/"))
+		}
+		fmt.Fprintf(tw, "shape\t%v\n", v.Shape)
+	}
+	if err := tw.Flush(); err != nil {
+		return err
+	}
+
+	if failed {
+		return errFailed
+	}
+	return nil
+}
+
+func validateIDs(stdout, stderr io.Writer, ids []string, patterns []string) error {
+	var failed bool
+	for _, s := range ids {
+		if err := validateID(s, patterns); err != nil {
+			failed = true
+			fmt.Fprintln(stderr, err)
+			continue
+		}
+		fmt.Fprintf(stdout, "ok\t%v\n", s)
+	}
+
+	if failed {
+		return errFailed
+	}
+	return nil
+}
+
+// validateID checks s against the patterns or, without patterns, that s is a valid id
+func validateID(s string, patterns []string) error {
+	if len(patterns) == 0 {
+		_, err := frn.Parse(s)
+		return err
+	}
+	return frn.Validate(frn.ID(s), patterns...)
+}
+
+func newIDs(w io.Writer, ns frn.Namespace, t frn.Type, count int) error {
+	if ns == "" || t == "" {
+		return fmt.Errorf("frn new: --ns and --type are required")
+	}
+	if _, err := frn.Parse(ns.New(t, "0").String()); err != nil {
+		return fmt.Errorf("frn new: invalid --ns or --type: %w", err)
+	}
+
+	for i := 0; i < count; i++ {
+		fmt.Fprintln(w, ns.New(t, frn.NewValue()))
+	}
+	return nil
+}
+
+func shapeIDs(w io.Writer, ids []string) error {
+	for _, s := range ids {
+		fmt.Fprintln(w, frn.ID(s).Shape())
+	}
+	return nil
+}
+
+func timeIDs(stdout, stderr io.Writer, ids []string) error {
+	var failed bool
+	for _, s := range ids {
+		createdAt, ok := frn.ID(s).CreatedAt()
+		if !ok {
+			failed = true
+			fmt.Fprintf(stderr, "frn %v: value is not a KSUID\n", s)
+			continue
+		}
+		fmt.Fprintf(stdout, "%v\t%v\n", createdAt.UTC().Format(time.RFC3339), s)
+	}
+
+	if failed {
+		return errFailed
+	}
+	return nil
+}
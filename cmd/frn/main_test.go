@@ -0,0 +1,118 @@
+package main
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/tj/assert"
+)
+
+func TestRun(t *testing.T) {
+	testCases := map[string]struct {
+		Args       []string
+		Stdin      string
+		WantCode   int
+		WantStdout string
+		WantStderr string
+	}{
+		"no command": {
+			WantCode:   2,
+			WantStderr: "usage: frn",
+		},
+		"unknown command": {
+			Args:       []string{"blah"},
+			WantCode:   2,
+			WantStderr: "unknown command, blah",
+		},
+		"parse": {
+			Args:       []string{"parse", "fm:crm:project:1:contract:2/key/value"},
+			WantStdout: "id       fm:crm:project:1:contract:2/key/value\nenv      fm\nservice  crm\ntype     project\nvalue    1\nchild    contract:2\npath     key/value\nshape    project/contract#key\n",
+		},
+		"parse json": {
+			Args:       []string{"parse", "--json", "fm:crm:project:1:contract:2/key"},
+			WantStdout: `{"id":"fm:crm:project:1:contract:2/key","env":"fm","service":"crm","type":"project","value":"1","children":[{"type":"contract","value":"2"}],"path":["key"],"shape":"project/contract#key"}` + "\n",
+		},
+		"parse invalid": {
+			Args:       []string{"parse", "--json", "fm:crm"},
+			WantCode:   1,
+			WantStdout: `{"id":"fm:crm","error":"invalid frn, \"fm:crm\": missing type at offset 6"}` + "\n",
+		},
+		"parse stdin": {
+			Args:       []string{"parse", "--json"},
+			Stdin:      "fm:crm:project:1\n\n  fm:crm:project:2  \n",
+			WantStdout: `{"id":"fm:crm:project:1","env":"fm","service":"crm","type":"project","value":"1","shape":"project"}` + "\n" + `{"id":"fm:crm:project:2","env":"fm","service":"crm","type":"project","value":"2","shape":"project"}` + "\n",
+		},
+		"validate": {
+			Args:       []string{"validate", "--pattern", "project/contract", "fm:crm:project:1:contract:2"},
+			WantStdout: "ok\tfm:crm:project:1:contract:2\n",
+		},
+		"validate alternatives": {
+			Args:       []string{"validate", "--pattern", "project", "--pattern", "project/contract", "fm:crm:project:1", "fm:crm:project:1:contract:2"},
+			WantStdout: "ok\tfm:crm:project:1\nok\tfm:crm:project:1:contract:2\n",
+		},
+		"validate mismatch": {
+			Args:       []string{"validate", "--pattern", "project/contract", "fm:crm:project:1:approval:2"},
+			WantCode:   1,
+			WantStderr: "frn fm:crm:project:1:approval:2: child type mismatch: want contract, got approval\n",
+		},
+		"validate without pattern": {
+			Args:       []string{"validate", "fm:crm:project:1", "fm:crm:project:1:contract:2/key"},
+			WantStdout: "ok\tfm:crm:project:1\nok\tfm:crm:project:1:contract:2/key\n",
+		},
+		"validate without pattern - invalid": {
+			Args:       []string{"validate", "fm:crm:project"},
+			WantCode:   1,
+			WantStderr: `invalid frn, "fm:crm:project": missing value at offset 14`,
+		},
+		"new": {
+			Args:       []string{"new", "--ns", "fm:crm", "--type", "project"},
+			WantStdout: "fm:crm:project:",
+		},
+		"new missing type": {
+			Args:       []string{"new", "--ns", "fm:crm"},
+			WantCode:   1,
+			WantStderr: "--ns and --type are required",
+		},
+		"shape": {
+			Args:       []string{"shape", "fm:crm:project:1:contract:2"},
+			WantStdout: "project/contract\n",
+		},
+		"time": {
+			Args:       []string{"time", "fm:crm:project:0ujtsYcgvSTl8PAuAdqWYSMnLOv"},
+			WantStdout: "2017-10-10T04:00:47Z\tfm:crm:project:0ujtsYcgvSTl8PAuAdqWYSMnLOv\n",
+		},
+		"time not ksuid": {
+			Args:       []string{"time", "fm:crm:project:1"},
+			WantCode:   1,
+			WantStderr: "value is not a KSUID",
+		},
+	}
+
+	for label, tc := range testCases {
+		t.Run(label, func(t *testing.T) {
+			var stdout, stderr bytes.Buffer
+			code := run(tc.Args, strings.NewReader(tc.Stdin), &stdout, &stderr)
+			assert.Equal(t, tc.WantCode, code, stderr.String())
+			if tc.WantCode == 0 && tc.Args[0] != "new" {
+				assert.Equal(t, tc.WantStdout, stdout.String())
+			} else {
+				assert.Contains(t, stdout.String(), tc.WantStdout)
+			}
+			assert.Contains(t, stderr.String(), tc.WantStderr)
+		})
+	}
+}
+
+func TestRun_NewCount(t *testing.T) {
+	var stdout, stderr bytes.Buffer
+	code := run([]string{"new", "--ns", "fm:crm", "--type", "project", "--count", "3"}, nil, &stdout, &stderr)
+	assert.Equal(t, 0, code)
+
+	lines, err := readLines(&stdout)
+	assert.Nil(t, err)
+	assert.Len(t, lines, 3)
+
+	code = run(append([]string{"validate", "--pattern", "project"}, lines...), nil, &stdout, &stderr)
+	assert.Equal(t, 0, code, stderr.String())
+}
@@ -0,0 +1,264 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"go/format"
+	"go/token"
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// maxDepth limits the number of types within a generated chain, which bounds schemas with recursive parents
+const maxDepth = 8
+
+// importPath of package frn; code generated for any other package qualifies the frn identifiers
+const importPath = "github.com/Freemodel-Inc/frn"
+
+// chain holds the types of an id from the root to the innermost child e.g. project, contract
+type chain []typeSchema
+
+// goName returns the Go name of the chain e.g. ProjectContract
+func (c chain) goName() string {
+	var s string
+	for _, t := range c {
+		s += t.Go
+	}
+	return s
+}
+
+// shape returns the shape of the chain e.g. project/contract
+func (c chain) shape() string {
+	var names []string
+	for _, t := range c {
+		names = append(names, t.Name)
+	}
+	return strings.Join(names, "/")
+}
+
+// constant is a generated constant
+type constant struct {
+	name  string
+	typ   string
+	value string
+}
+
+// generate returns the formatted Go source for the schema
+func generate(s schema, pkg, source string) ([]byte, error) {
+	q := "frn."
+	if pkg == "frn" {
+		q = ""
+	}
+
+	var (
+		services     []constant
+		types        = map[string]constant{}
+		shapes       = map[string]constant{}
+		patterns     []constant
+		constructors = map[string]chain{}
+	)
+	addConst := func(m map[string]constant, c constant) error {
+		if prev, ok := m[c.name]; ok && prev != c {
+			return fmt.Errorf("%v is generated for both %v and %v", c.name, prev.value, c.value)
+		}
+		m[c.name] = c
+		return nil
+	}
+
+	for _, svc := range s.Services {
+		services = append(services, constant{name: "Service" + svc.Go, typ: q + "Service", value: svc.Name})
+
+		for _, t := range svc.Types {
+			if err := addConst(types, constant{name: "Type" + t.Go, typ: q + "Type", value: t.Name}); err != nil {
+				return nil, err
+			}
+		}
+
+		for _, c := range svc.chains() {
+			name := c.goName()
+			if prev, ok := constructors[name]; ok && prev.shape() != c.shape() {
+				return nil, fmt.Errorf("New%vID is generated for both %v and %v", name, prev.shape(), c.shape())
+			}
+			constructors[name] = c
+
+			variants := []constant{{name: name, value: c.shape()}}
+			for _, head := range c[len(c)-1].PathHeads {
+				variants = append(variants, constant{name: name + goName(head) + "Path", value: c.shape() + "#" + head})
+			}
+			for _, v := range variants {
+				if err := addConst(shapes, constant{name: "Shape" + v.name, value: v.value}); err != nil {
+					return nil, err
+				}
+				patterns = append(patterns, constant{name: "Pattern" + svc.Go + v.name, value: svc.Name + ":" + v.value})
+			}
+		}
+	}
+
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "// Code generated by frngen from %v; DO NOT EDIT.\n\n", source)
+	fmt.Fprintf(&buf, "package %v\n\n", pkg)
+	if q != "" && (len(types) > 0 || len(services) > 0) {
+		fmt.Fprintf(&buf, "import %q\n\n", importPath)
+	}
+
+	writeConsts(&buf, "", services)
+	writeConsts(&buf, "", sortedConsts(types))
+	writeConsts(&buf, "// Shapes of the ids of each type e.g. for frn.Kind or frn.CompilePattern\n", sortedConsts(shapes))
+	writeConsts(&buf, "// Patterns of the ids of each type qualified by service e.g. for frn.Validate\n", patterns)
+
+	var names []string
+	for name := range constructors {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		writeConstructor(&buf, q, name, constructors[name])
+	}
+
+	if len(types) > 0 {
+		writeRegisterSchema(&buf, q, s)
+	}
+
+	src, err := format.Source(buf.Bytes())
+	if err != nil {
+		return nil, fmt.Errorf("unable to format generated code: %w", err)
+	}
+	return src, nil
+}
+
+// chains returns the chains of every type within the service, in the order the types are declared
+func (svc serviceSchema) chains() []chain {
+	byName := map[string]typeSchema{}
+	for _, t := range svc.Types {
+		byName[t.Name] = t
+	}
+
+	var chainsTo func(t typeSchema, seen map[string]bool) []chain
+	chainsTo = func(t typeSchema, seen map[string]bool) []chain {
+		var result []chain
+		if len(t.Parents) == 0 || t.Root {
+			result = append(result, chain{t})
+		}
+		if len(seen) >= maxDepth-1 {
+			return result
+		}
+
+		seen[t.Name] = true
+		defer delete(seen, t.Name)
+		for _, parent := range t.Parents {
+			if seen[parent] {
+				continue
+			}
+			for _, c := range chainsTo(byName[parent], seen) {
+				result = append(result, append(append(chain{}, c...), t))
+			}
+		}
+		return result
+	}
+
+	var result []chain
+	for _, t := range svc.Types {
+		result = append(result, chainsTo(t, map[string]bool{})...)
+	}
+	return result
+}
+
+func sortedConsts(m map[string]constant) []constant {
+	var cc []constant
+	for _, c := range m {
+		cc = append(cc, c)
+	}
+	sort.Slice(cc, func(i, j int) bool { return cc[i].name < cc[j].name })
+	return cc
+}
+
+func writeConsts(buf *bytes.Buffer, doc string, cc []constant) {
+	if len(cc) == 0 {
+		return
+	}
+	buf.WriteString(doc)
+	buf.WriteString("const (\n")
+	for _, c := range cc {
+		if c.typ == "" {
+			fmt.Fprintf(buf, "\t%v = %q\n", c.name, c.value)
+		} else {
+			fmt.Fprintf(buf, "\t%v %v = %q\n", c.name, c.typ, c.value)
+		}
+	}
+	buf.WriteString(")\n\n")
+}
+
+func writeConstructor(buf *bytes.Buffer, q, name string, c chain) {
+	var (
+		params  []string
+		example []string
+		expr    string
+	)
+	for i, t := range c {
+		param := paramName(t.Go)
+		params = append(params, param)
+		example = append(example, t.Name, "<"+param+">")
+		if i == 0 {
+			expr = fmt.Sprintf("ns.New(Type%v, %v)", t.Go, param)
+		} else {
+			expr += fmt.Sprintf(".Sub(Type%v, %v)", t.Go, param)
+		}
+	}
+
+	fmt.Fprintf(buf, "// New%vID returns the id of the %v e.g. <ns>:%v\n", name, c[len(c)-1].Name, strings.Join(example, ":"))
+	fmt.Fprintf(buf, "func New%vID(ns %vNamespace, %v string) %vID {\n", name, q, strings.Join(params, ", "), q)
+	fmt.Fprintf(buf, "\treturn %v\n}\n\n", expr)
+}
+
+func writeRegisterSchema(buf *bytes.Buffer, q string, s schema) {
+	buf.WriteString("// RegisterSchema registers the types of each service with the registry\n")
+	fmt.Fprintf(buf, "func RegisterSchema(r *%vRegistry) *%vRegistry {\n", q, q)
+	buf.WriteString("\treturn r")
+	for _, svc := range s.Services {
+		if len(svc.Types) == 0 {
+			continue
+		}
+		goNames := map[string]string{}
+		for _, t := range svc.Types {
+			goNames[t.Name] = t.Go
+		}
+
+		fmt.Fprintf(buf, ".\n\t\tRegister(Service%v,\n", svc.Go)
+		for _, t := range svc.Types {
+			fields := []string{"Type: Type" + t.Go}
+			if len(t.Parents) > 0 {
+				var parents []string
+				for _, parent := range t.Parents {
+					parents = append(parents, "Type"+goNames[parent])
+				}
+				fields = append(fields, fmt.Sprintf("Parents: []%vType{%v}", q, strings.Join(parents, ", ")))
+			}
+			if t.Root {
+				fields = append(fields, "Root: true")
+			}
+			if len(t.PathHeads) > 0 {
+				fields = append(fields, fmt.Sprintf("PathHeads: %#v", t.PathHeads))
+			}
+			fmt.Fprintf(buf, "\t\t\t%vTypeSpec{%v},\n", q, strings.Join(fields, ", "))
+		}
+		buf.WriteString("\t\t)")
+	}
+	buf.WriteString("\n}\n")
+}
+
+// paramName returns the Go name as an unexported parameter name e.g. ContractChange => contractChange
+func paramName(s string) string {
+	runes := []rune(s)
+	for i := 0; i < len(runes) && unicode.IsUpper(runes[i]); i++ {
+		if i > 0 && i+1 < len(runes) && unicode.IsLower(runes[i+1]) {
+			break // e.g. CRMProject => crmProject
+		}
+		runes[i] = unicode.ToLower(runes[i])
+	}
+	name := string(runes)
+	if token.IsKeyword(name) {
+		name += "Value"
+	}
+	return name
+}
@@ -0,0 +1,76 @@
+// Command frngen generates Go code from a YAML or JSON schema of services, types, child relations and path heads:
+// Service and Type constants, shape and pattern constants, id constructors and a RegisterSchema func.
+//
+//	//go:generate go run github.com/Freemodel-Inc/frn/cmd/frngen -schema schema.yaml -out schema_gen.go
+//
+// A schema lists the services and the types within each; go overrides the derived Go name e.g. for acronyms.
+//
+//	services:
+//	  - name: crm
+//	    go: CRM
+//	    types:
+//	      - name: project
+//	        path_heads: [account]
+//	      - name: contract
+//	        parents: [project]
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+)
+
+func main() {
+	os.Exit(run(os.Args[1:], os.Stdout, os.Stderr))
+}
+
+// run executes the command in args and returns the exit code; 1 if generation failed, 2 if the flags could not be parsed
+func run(args []string, stdout, stderr io.Writer) int {
+	fs := flag.NewFlagSet("frngen", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+
+	var (
+		schemaPath = fs.String("schema", "schema.yaml", "path to the yaml or json schema")
+		out        = fs.String("out", "", "path of the generated file; defaults to stdout")
+		pkg        = fs.String("package", os.Getenv("GOPACKAGE"), "package of the generated file; defaults to $GOPACKAGE")
+	)
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+	if *pkg == "" {
+		fmt.Fprintln(stderr, "frngen: -package is required outside of go generate")
+		return 2
+	}
+
+	data, err := os.ReadFile(*schemaPath)
+	if err != nil {
+		fmt.Fprintf(stderr, "frngen: %v\n", err)
+		return 1
+	}
+
+	s, err := parseSchema(data)
+	if err != nil {
+		fmt.Fprintf(stderr, "frngen: %v: %v\n", *schemaPath, err)
+		return 1
+	}
+
+	src, err := generate(s, *pkg, filepath.Base(*schemaPath))
+	if err != nil {
+		fmt.Fprintf(stderr, "frngen: %v\n", err)
+		return 1
+	}
+
+	if *out == "" {
+		_, err = stdout.Write(src)
+	} else {
+		err = os.WriteFile(*out, src, 0644)
+	}
+	if err != nil {
+		fmt.Fprintf(stderr, "frngen: %v\n", err)
+		return 1
+	}
+	return 0
+}
@@ -0,0 +1,156 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/tj/assert"
+)
+
+const testSchema = `
+services:
+  - name: crm
+    go: CRM
+    types:
+      - name: project
+        path_heads: [account]
+      - name: contract
+        parents: [project]
+      - name: change-order
+        parents: [contract]
+`
+
+func TestGenerate(t *testing.T) {
+	s, err := parseSchema([]byte(testSchema))
+	assert.Nil(t, err)
+
+	testCases := map[string]struct {
+		Package string
+		Want    []string
+	}{
+		"external": {
+			Package: "billing",
+			Want: []string{
+				"package billing",
+				`import "github.com/Freemodel-Inc/frn"`,
+				`ServiceCRM frn.Service = "crm"`,
+				`TypeChangeOrder frn.Type = "change-order"`,
+				`ShapeProjectContractChangeOrder         = "project/contract/change-order"`,
+				`ShapeProjectAccountPath                 = "project#account"`,
+				`PatternCRMProjectContract               = "crm:project/contract"`,
+				"func NewProjectContractID(ns frn.Namespace, project, contract string) frn.ID {\n\treturn ns.New(TypeProject, project).Sub(TypeContract, contract)\n}",
+				"func RegisterSchema(r *frn.Registry) *frn.Registry {",
+				`frn.TypeSpec{Type: TypeContract, Parents: []frn.Type{TypeProject}},`,
+			},
+		},
+		"frn": {
+			Package: "frn",
+			Want: []string{
+				"package frn\n\nconst",
+				"func NewProjectID(ns Namespace, project string) ID {",
+				`TypeSpec{Type: TypeProject, PathHeads: []string{"account"}},`,
+			},
+		},
+	}
+
+	for label, tc := range testCases {
+		t.Run(label, func(t *testing.T) {
+			src, err := generate(s, tc.Package, "schema.yaml")
+			assert.Nil(t, err)
+			// compare with whitespace collapsed, so the assertions do not depend on gofmt alignment
+			got := strings.Join(strings.Fields(string(src)), " ")
+			for _, want := range tc.Want {
+				assert.Contains(t, got, strings.Join(strings.Fields(want), " "))
+			}
+		})
+	}
+}
+
+func TestParseSchema(t *testing.T) {
+	testCases := map[string]struct {
+		Schema  string
+		WantErr string
+	}{
+		"json": {
+			Schema: `{"services": [{"name": "crm", "types": [{"name": "project"}]}]}`,
+		},
+		"invalid service": {
+			Schema:  `{"services": [{"name": "crm:x"}]}`,
+			WantErr: "invalid name",
+		},
+		"duplicate type": {
+			Schema:  `{"services": [{"name": "crm", "types": [{"name": "project"}, {"name": "project"}]}]}`,
+			WantErr: "duplicate type",
+		},
+		"unknown parent": {
+			Schema:  `{"services": [{"name": "crm", "types": [{"name": "contract", "parents": ["project"]}]}]}`,
+			WantErr: "unknown parent",
+		},
+	}
+
+	for label, tc := range testCases {
+		t.Run(label, func(t *testing.T) {
+			_, err := parseSchema([]byte(tc.Schema))
+			if tc.WantErr == "" {
+				assert.Nil(t, err)
+				return
+			}
+			assert.NotNil(t, err)
+			assert.Contains(t, err.Error(), tc.WantErr)
+		})
+	}
+}
+
+func TestGenerate_Recursive(t *testing.T) {
+	s, err := parseSchema([]byte(`{"services": [{"name": "crm", "types": [{"name": "folder", "parents": ["folder"], "root": true}]}]}`))
+	assert.Nil(t, err)
+
+	src, err := generate(s, "frn", "schema.yaml")
+	assert.Nil(t, err)
+	assert.Contains(t, string(src), "func NewFolderID(")
+	assert.NotContains(t, string(src), "NewFolderFolderID")
+}
+
+func TestNames(t *testing.T) {
+	testCases := map[string]struct {
+		Name      string
+		WantGo    string
+		WantParam string
+	}{
+		"simple":  {Name: "project", WantGo: "Project", WantParam: "project"},
+		"kebab":   {Name: "change-order", WantGo: "ChangeOrder", WantParam: "changeOrder"},
+		"snake":   {Name: "line_item", WantGo: "LineItem", WantParam: "lineItem"},
+		"keyword": {Name: "type", WantGo: "Type", WantParam: "typeValue"},
+		"digit":   {Name: "1099", WantGo: "X1099", WantParam: "x1099"},
+	}
+
+	for label, tc := range testCases {
+		t.Run(label, func(t *testing.T) {
+			assert.Equal(t, tc.WantGo, goName(tc.Name))
+			assert.Equal(t, tc.WantParam, paramName(goName(tc.Name)))
+		})
+	}
+	assert.Equal(t, "crmProject", paramName("CRMProject"))
+}
+
+// TestRun_UpToDate ensures the generated code within package frn matches schema.yaml
+func TestRun_UpToDate(t *testing.T) {
+	root := filepath.Join("..", "..")
+	want, err := os.ReadFile(filepath.Join(root, "schema_gen.go"))
+	assert.Nil(t, err)
+
+	var stdout, stderr bytes.Buffer
+	code := run([]string{"-schema", filepath.Join(root, "schema.yaml"), "-package", "frn"}, &stdout, &stderr)
+	assert.Equal(t, 0, code, stderr.String())
+	assert.Equal(t, string(want), stdout.String(), "run go generate")
+}
+
+func TestRun_Errors(t *testing.T) {
+	var stdout, stderr bytes.Buffer
+	assert.Equal(t, 2, run([]string{"-package", ""}, &stdout, &stderr))
+	assert.Equal(t, 1, run([]string{"-schema", "missing.yaml", "-package", "frn"}, &stdout, &stderr))
+	assert.True(t, strings.Contains(stderr.String(), "missing.yaml"))
+}
@@ -0,0 +1,97 @@
+package main
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"unicode"
+
+	"gopkg.in/yaml.v3"
+)
+
+// reName matches the names of services and types
+var reName = regexp.MustCompile(`^[a-zA-Z0-9_-]+$`)
+
+// schema describes the services and types to generate; json is a subset of yaml, so either may be used
+type schema struct {
+	Services []serviceSchema `yaml:"services"`
+}
+
+type serviceSchema struct {
+	Name  string       `yaml:"name"`
+	Go    string       `yaml:"go"` // Go name of the service e.g. CRM => ServiceCRM; derived from Name if blank
+	Types []typeSchema `yaml:"types"`
+}
+
+type typeSchema struct {
+	Name      string   `yaml:"name"`
+	Go        string   `yaml:"go"`         // Go name of the type e.g. Project => TypeProject; derived from Name if blank
+	Parents   []string `yaml:"parents"`    // Parents the type may be nested under
+	Root      bool     `yaml:"root"`       // Root additionally permits the type to appear as the root when Parents are set
+	PathHeads []string `yaml:"path_heads"` // PathHeads that may follow the type e.g. account
+}
+
+// parseSchema decodes and checks the schema
+func parseSchema(data []byte) (schema, error) {
+	var s schema
+	if err := yaml.Unmarshal(data, &s); err != nil {
+		return schema{}, err
+	}
+
+	services := map[string]struct{}{}
+	for i, svc := range s.Services {
+		if !reName.MatchString(svc.Name) {
+			return schema{}, fmt.Errorf("service %v: invalid name, %q", i, svc.Name)
+		}
+		if _, ok := services[svc.Name]; ok {
+			return schema{}, fmt.Errorf("service %v: duplicate service", svc.Name)
+		}
+		services[svc.Name] = struct{}{}
+		if s.Services[i].Go == "" {
+			s.Services[i].Go = goName(svc.Name)
+		}
+
+		types := map[string]struct{}{}
+		for j, t := range svc.Types {
+			if !reName.MatchString(t.Name) {
+				return schema{}, fmt.Errorf("service %v: type %v: invalid name, %q", svc.Name, j, t.Name)
+			}
+			if _, ok := types[t.Name]; ok {
+				return schema{}, fmt.Errorf("service %v: duplicate type, %v", svc.Name, t.Name)
+			}
+			types[t.Name] = struct{}{}
+			if svc.Types[j].Go == "" {
+				svc.Types[j].Go = goName(t.Name)
+			}
+		}
+		for _, t := range svc.Types {
+			for _, parent := range t.Parents {
+				if _, ok := types[parent]; !ok {
+					return schema{}, fmt.Errorf("service %v: type %v: unknown parent, %v", svc.Name, t.Name, parent)
+				}
+			}
+			for _, head := range t.PathHeads {
+				if !reName.MatchString(head) {
+					return schema{}, fmt.Errorf("service %v: type %v: invalid path head, %q", svc.Name, t.Name, head)
+				}
+			}
+		}
+	}
+
+	return s, nil
+}
+
+// goName converts a name into an exported Go identifier e.g. contract-change => ContractChange
+func goName(s string) string {
+	var b strings.Builder
+	for _, part := range strings.FieldsFunc(s, func(r rune) bool { return r == '-' || r == '_' }) {
+		runes := []rune(part)
+		runes[0] = unicode.ToUpper(runes[0])
+		b.WriteString(string(runes))
+	}
+	name := b.String()
+	if name == "" || unicode.IsDigit([]rune(name)[0]) {
+		name = "X" + name
+	}
+	return name
+}
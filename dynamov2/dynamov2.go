@@ -0,0 +1,123 @@
+// Package dynamov2 marshals frn ids with aws-sdk-go-v2 attributevalue. It follows the same policy as the
+// aws-sdk-go v1 marshalers of package frn: empty marshals as NULL and NULL or a missing attribute unmarshals as empty;
+// blank ids within a set are dropped.
+//
+// Unlike v1, attributevalue decides omitempty from the Go value rather than the marshaled attribute, so omitempty omits
+// blank ids and nil sets and pointers, but a non-nil empty set, or pointer to a blank id, is written as NULL.
+//
+//	type Project struct {
+//	  ID       dynamov2.ID    `dynamodbav:"id"`
+//	  Contacts dynamov2.IDSet `dynamodbav:"contacts,omitempty"`
+//	}
+package dynamov2
+
+import (
+	"fmt"
+
+	"github.com/Freemodel-Inc/frn"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+)
+
+// ID is a frn.ID that implements attributevalue.Marshaler and attributevalue.Unmarshaler
+type ID frn.ID
+
+// FRN returns the id as a frn.ID
+func (id ID) FRN() frn.ID {
+	return frn.ID(id)
+}
+
+// MarshalDynamoDBAttributeValue marshals the id as S or, if empty, NULL
+func (id ID) MarshalDynamoDBAttributeValue() (types.AttributeValue, error) {
+	if id == "" {
+		return &types.AttributeValueMemberNULL{Value: true}, nil
+	}
+	return &types.AttributeValueMemberS{Value: string(id)}, nil
+}
+
+// UnmarshalDynamoDBAttributeValue unmarshals S or, as an empty id, NULL
+func (id *ID) UnmarshalDynamoDBAttributeValue(av types.AttributeValue) error {
+	switch v := av.(type) {
+	case nil, *types.AttributeValueMemberNULL:
+		*id = ""
+	case *types.AttributeValueMemberS:
+		*id = ID(v.Value)
+	default:
+		return fmt.Errorf("unable to unmarshal key: want S or NULL attribute")
+	}
+	return nil
+}
+
+// IDSet is a frn.IDSet that implements attributevalue.Marshaler and attributevalue.Unmarshaler
+type IDSet frn.IDSet
+
+// FRN returns the set as a frn.IDSet
+func (vv IDSet) FRN() frn.IDSet {
+	return frn.IDSet(vv)
+}
+
+// MarshalDynamoDBAttributeValue marshals the non-blank ids as SS or, if there are none, NULL
+func (vv IDSet) MarshalDynamoDBAttributeValue() (types.AttributeValue, error) {
+	return marshalSet(frn.IDSet(vv).Trim())
+}
+
+// UnmarshalDynamoDBAttributeValue unmarshals SS, L of S or, as an empty set, NULL
+func (vv *IDSet) UnmarshalDynamoDBAttributeValue(av types.AttributeValue) error {
+	set, err := unmarshalSet(av)
+	if err != nil {
+		return err
+	}
+	*vv = IDSet(set)
+	return nil
+}
+
+// KeySet is a frn.KeySet that implements attributevalue.Marshaler and attributevalue.Unmarshaler
+type KeySet frn.KeySet
+
+// FRN returns the set as a frn.KeySet
+func (kk KeySet) FRN() frn.KeySet {
+	return frn.KeySet(kk)
+}
+
+// MarshalDynamoDBAttributeValue marshals the distinct keys as SS or, if there are none, NULL
+func (kk KeySet) MarshalDynamoDBAttributeValue() (types.AttributeValue, error) {
+	return marshalSet(frn.IDSet(kk).Trim().Dedupe())
+}
+
+// UnmarshalDynamoDBAttributeValue unmarshals SS, L of S or, as an empty set, NULL
+func (kk *KeySet) UnmarshalDynamoDBAttributeValue(av types.AttributeValue) error {
+	set, err := unmarshalSet(av)
+	if err != nil {
+		return err
+	}
+	*kk = KeySet(frn.NewKeySet(set.Strings()...))
+	return nil
+}
+
+func marshalSet(set frn.IDSet) (types.AttributeValue, error) {
+	if len(set) == 0 {
+		return &types.AttributeValueMemberNULL{Value: true}, nil
+	}
+	return &types.AttributeValueMemberSS{Value: set.Strings()}, nil
+}
+
+func unmarshalSet(av types.AttributeValue) (frn.IDSet, error) {
+	var set frn.IDSet
+	switch v := av.(type) {
+	case nil, *types.AttributeValueMemberNULL:
+	case *types.AttributeValueMemberSS:
+		for _, s := range v.Value {
+			set = append(set, frn.ID(s))
+		}
+	case *types.AttributeValueMemberL: // e.g. written as a []string
+		for _, item := range v.Value {
+			s, ok := item.(*types.AttributeValueMemberS)
+			if !ok {
+				return nil, fmt.Errorf("unable to unmarshal set: want list of S attributes")
+			}
+			set = append(set, frn.ID(s.Value))
+		}
+	default:
+		return nil, fmt.Errorf("unable to unmarshal set: want SS, L or NULL attribute")
+	}
+	return set, nil
+}
@@ -0,0 +1,121 @@
+package dynamov2
+
+import (
+	"testing"
+
+	"github.com/Freemodel-Inc/frn"
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/attributevalue"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/tj/assert"
+)
+
+func TestEmptyIsNull(t *testing.T) {
+	type Record struct {
+		ID         ID
+		IDPtr      *ID
+		IDSet      IDSet
+		KeySet     KeySet
+		OmitID     ID     `dynamodbav:",omitempty"`
+		OmitIDPtr  *ID    `dynamodbav:",omitempty"`
+		OmitIDSet  IDSet  `dynamodbav:",omitempty"`
+		OmitKeySet KeySet `dynamodbav:",omitempty"`
+	}
+
+	var (
+		id    = ID("fm:crm:project:1")
+		empty = ID("")
+	)
+
+	testCases := map[string]struct {
+		Record   Record
+		Want     Record
+		WantKeys []string // WantKeys holds the attributes that are not NULL
+		WantNull []string
+	}{
+		"empty": {
+			Record:   Record{},
+			Want:     Record{},
+			WantNull: []string{"ID", "IDPtr", "IDSet", "KeySet"},
+		},
+		"empty values": {
+			Record: Record{
+				IDPtr:      &empty,
+				IDSet:      IDSet{},
+				KeySet:     KeySet{},
+				OmitIDPtr:  &empty,
+				OmitIDSet:  IDSet{""},
+				OmitKeySet: KeySet{""},
+			},
+			Want:     Record{},
+			WantNull: []string{"ID", "IDPtr", "IDSet", "KeySet", "OmitIDPtr", "OmitIDSet", "OmitKeySet"}, // see package doc
+		},
+		"present": {
+			Record: Record{
+				ID:         id,
+				IDPtr:      &id,
+				IDSet:      IDSet{frn.ID(id)},
+				KeySet:     KeySet{frn.ID(id), frn.ID(id)},
+				OmitID:     id,
+				OmitIDPtr:  &id,
+				OmitIDSet:  IDSet{frn.ID(id), ""},
+				OmitKeySet: KeySet{frn.ID(id)},
+			},
+			Want: Record{
+				ID:         id,
+				IDPtr:      &id,
+				IDSet:      IDSet{frn.ID(id)},
+				KeySet:     KeySet{frn.ID(id)},
+				OmitID:     id,
+				OmitIDPtr:  &id,
+				OmitIDSet:  IDSet{frn.ID(id)},
+				OmitKeySet: KeySet{frn.ID(id)},
+			},
+			WantKeys: []string{"ID", "IDPtr", "IDSet", "KeySet", "OmitID", "OmitIDPtr", "OmitIDSet", "OmitKeySet"},
+		},
+	}
+
+	for label, tc := range testCases {
+		t.Run(label, func(t *testing.T) {
+			item, err := attributevalue.MarshalMap(tc.Record)
+			assert.Nil(t, err)
+
+			var keys, nulls []string
+			for k, v := range item {
+				if _, ok := v.(*types.AttributeValueMemberNULL); ok {
+					nulls = append(nulls, k)
+				} else {
+					keys = append(keys, k)
+				}
+			}
+			assert.ElementsMatch(t, tc.WantKeys, keys)
+			assert.ElementsMatch(t, tc.WantNull, nulls)
+
+			var got Record
+			err = attributevalue.UnmarshalMap(item, &got)
+			assert.Nil(t, err)
+			assert.Equal(t, tc.Want, got)
+		})
+	}
+}
+
+func TestUnmarshal(t *testing.T) {
+	null := &types.AttributeValueMemberNULL{Value: true}
+
+	id := ID("fm:crm:project:1")
+	assert.Nil(t, attributevalue.Unmarshal(null, &id))
+	assert.Equal(t, ID(""), id)
+
+	set := IDSet{"fm:crm:project:1"}
+	assert.Nil(t, attributevalue.Unmarshal(null, &set))
+	assert.Nil(t, set)
+
+	keys := KeySet{"fm:crm:project:1"}
+	assert.Nil(t, attributevalue.Unmarshal(null, &keys))
+	assert.Nil(t, keys)
+
+	list := &types.AttributeValueMemberL{Value: []types.AttributeValue{&types.AttributeValueMemberS{Value: "fm:crm:project:1"}}}
+	assert.Nil(t, attributevalue.Unmarshal(list, &set))
+	assert.Equal(t, frn.IDSet{"fm:crm:project:1"}, set.FRN())
+
+	assert.NotNil(t, attributevalue.Unmarshal(&types.AttributeValueMemberN{Value: "1"}, &id))
+}
@@ -0,0 +1,36 @@
+package frn
+
+import "fmt"
+
+// Part identifies the part of an id that failed to match a pattern
+type Part string
+
+const (
+	PartEnv        Part = "env"
+	PartService    Part = "service"
+	PartParentType Part = "parent type"
+	PartChild      Part = "child"
+	PartChildType  Part = "child type"
+	PartPath       Part = "path"
+	PartPathHead   Part = "path head"
+	PartPattern    Part = "pattern" // PartPattern is used when the id matched a negated alternative or none of several alternatives
+)
+
+// MismatchError describes which part of an id did not match a pattern and what was found
+type MismatchError struct {
+	ID   ID
+	Part Part
+	Want string // Want holds what the pattern expected e.g. project; blank if the part must be absent
+	Got  string // Got holds what the id contained e.g. contract; blank if the part is missing
+}
+
+func (e *MismatchError) Error() string {
+	switch {
+	case e.Got == "":
+		return fmt.Sprintf("frn %v: missing %v", e.ID, e.Part)
+	case e.Want == "":
+		return fmt.Sprintf("frn %v: unexpected %v, %v", e.ID, e.Part, e.Got)
+	default:
+		return fmt.Sprintf("frn %v: %v mismatch: want %v, got %v", e.ID, e.Part, e.Want, e.Got)
+	}
+}
@@ -0,0 +1,94 @@
+package frn
+
+import (
+	"strings"
+)
+
+// escapeChar introduces an escaped byte, followed by two lowercase hex digits e.g. @ => %40
+const escapeChar = '%'
+
+const hexDigits = "0123456789abcdef"
+
+// EscapeValue escapes s for use as the value of an id, so that any external key can become part of a valid id
+// e.g. jo.smith@example.com => jo%2esmith%40example%2ecom. New, Sub and the factories escape values automatically;
+// EscapeValue is needed only when building ids or globs by hand.
+func EscapeValue(s string) string {
+	return escape(s, isValueChar)
+}
+
+// EscapePath escapes s for use as a path segment e.g. Q3 => %513; WithPath and WithPathSegments escape automatically
+func EscapePath(s string) string {
+	return escape(s, isPathChar)
+}
+
+// Unescape reverses EscapeValue and EscapePath; invalid escapes, including uppercase hex, are left as is
+func Unescape(s string) string {
+	if strings.IndexByte(s, escapeChar) == -1 {
+		return s
+	}
+
+	var b strings.Builder
+	b.Grow(len(s))
+	for i := 0; i < len(s); i++ {
+		if s[i] == escapeChar && i+2 < len(s) && isHex(s[i+1]) && isHex(s[i+2]) {
+			b.WriteByte(unhex(s[i+1])<<4 | unhex(s[i+2]))
+			i += 2
+			continue
+		}
+		b.WriteByte(s[i])
+	}
+	return b.String()
+}
+
+// escape replaces each byte of s not permitted by allowed with its escaped form
+func escape(s string, allowed func(r rune) bool) string {
+	n := 0
+	for i := 0; i < len(s); i++ {
+		if !allowed(rune(s[i])) {
+			n++
+		}
+	}
+	if n == 0 {
+		return s
+	}
+
+	var b strings.Builder
+	b.Grow(len(s) + 2*n)
+	for i := 0; i < len(s); i++ {
+		if c := s[i]; allowed(rune(c)) {
+			b.WriteByte(c)
+		} else {
+			b.WriteByte(escapeChar)
+			b.WriteByte(hexDigits[c>>4])
+			b.WriteByte(hexDigits[c&0x0f])
+		}
+	}
+	return b.String()
+}
+
+// invalidEscape returns the index of the first escape in s that EscapeValue or EscapePath would not have produced for
+// allowed, or -1. Only the canonical form is accepted, two lowercase hex digits for a byte allowed forbids, so that
+// each id has a single spelling.
+func invalidEscape(s string, allowed func(r rune) bool) int {
+	for i := 0; i < len(s); i++ {
+		if s[i] != escapeChar {
+			continue
+		}
+		if i+2 >= len(s) || !isHex(s[i+1]) || !isHex(s[i+2]) || allowed(rune(unhex(s[i+1])<<4|unhex(s[i+2]))) {
+			return i
+		}
+		i += 2
+	}
+	return -1
+}
+
+func isHex(c byte) bool {
+	return c >= '0' && c <= '9' || c >= 'a' && c <= 'f'
+}
+
+func unhex(c byte) byte {
+	if c >= 'a' {
+		return c - 'a' + 10
+	}
+	return c - '0'
+}
@@ -0,0 +1,135 @@
+package frn
+
+import (
+	"testing"
+
+	"github.com/tj/assert"
+)
+
+func TestEscapeValue(t *testing.T) {
+	testCases := map[string]struct {
+		Value string
+		Want  string
+	}{
+		"unreserved": {
+			Value: "Abc-123_x",
+			Want:  "Abc-123_x",
+		},
+		"email": {
+			Value: "jo.smith@example.com",
+			Want:  "jo%2esmith%40example%2ecom",
+		},
+		"separators": {
+			Value: "a:b/c",
+			Want:  "a%3ab%2fc",
+		},
+		"escape char": {
+			Value: "100%",
+			Want:  "100%25",
+		},
+		"utf-8": {
+			Value: "é",
+			Want:  "%c3%a9",
+		},
+	}
+
+	for label, tc := range testCases {
+		t.Run(label, func(t *testing.T) {
+			got := EscapeValue(tc.Value)
+			assert.Equal(t, tc.Want, got)
+			assert.Equal(t, tc.Value, Unescape(got))
+
+			id := NewNamespace("", ServiceCRM).New(TypeEntity, tc.Value)
+			assert.True(t, id.IsValid(), id)
+			assert.Equal(t, tc.Value, id.Value())
+
+			child := id.Sub(TypeEvent, tc.Value)
+			assert.True(t, child.IsValid(), child)
+			assert.Equal(t, tc.Value, child.Child().Value())
+			assert.Equal(t, id, child.Parent())
+		})
+	}
+
+	assert.Equal(t, "%413", EscapePath("A3"))
+	assert.Equal(t, "50%", Unescape("50%"))
+	assert.Equal(t, "%zz", Unescape("%zz"))
+	assert.Equal(t, "%2E", Unescape("%2E"))
+}
+
+func TestEscape_Path(t *testing.T) {
+	id := ID("fm:fin:entity:1").WithPath("account", "AR", "2024.q3")
+	assert.Equal(t, ID("fm:fin:entity:1/account/%41%52/2024%2eq3"), id)
+	assert.True(t, id.IsValid())
+	assert.Equal(t, []string{"account", "AR", "2024.q3"}, id.PathSegments())
+
+	head, tail, ok := id.Path()
+	assert.True(t, ok)
+	assert.Equal(t, "account", head)
+	assert.Equal(t, "AR/2024.q3", tail)
+
+	replaced, ok := id.ReplacePathSegment(2, "2025")
+	assert.True(t, ok)
+	assert.Equal(t, ID("fm:fin:entity:1/account/%41%52/2025"), replaced)
+	assert.Equal(t, id, id.WithPathSegments(id.PathSegments()...))
+
+	split := ID("fm:fin:entity:1").WithPathSegments("account", "a/b", "c")
+	joined := ID("fm:fin:entity:1").WithPathSegments("account", "a", "b", "c")
+	assert.NotEqual(t, split, joined)
+	assert.Equal(t, []string{"account", "a/b", "c"}, split.PathSegments())
+	assert.Equal(t, []string{"account", "a", "b", "c"}, joined.PathSegments())
+}
+
+func TestParse_Escapes(t *testing.T) {
+	testCases := map[string]struct {
+		ID      string
+		WantErr string
+	}{
+		"ok": {
+			ID: "fm:crm:contact:jo%40example%2ecom:note:%2e/a%2fb/%41",
+		},
+		"uppercase hex": {
+			ID:      "fm:crm:contact:jo%40example%2Ecom",
+			WantErr: `invalid frn, "fm:crm:contact:jo%40example%2Ecom": invalid escape in value at offset 27`,
+		},
+		"unreserved in value": {
+			ID:      "fm:crm:contact:%61",
+			WantErr: `invalid frn, "fm:crm:contact:%61": invalid escape in value at offset 15`,
+		},
+		"unreserved in child value": {
+			ID:      "fm:crm:contact:1:note:%41",
+			WantErr: `invalid frn, "fm:crm:contact:1:note:%41": invalid escape in child value at offset 22`,
+		},
+		"unreserved in path": {
+			ID:      "fm:crm:contact:1/%61",
+			WantErr: `invalid frn, "fm:crm:contact:1/%61": invalid escape in path at offset 17`,
+		},
+		"truncated": {
+			ID:      "fm:crm:contact:jo%4",
+			WantErr: `invalid frn, "fm:crm:contact:jo%4": invalid escape in value at offset 17`,
+		},
+		"invalid hex": {
+			ID:      "fm:crm:contact:1/a%zz",
+			WantErr: `invalid frn, "fm:crm:contact:1/a%zz": invalid escape in path at offset 18`,
+		},
+		"not in type": {
+			ID:      "fm:crm:con%41tact:1",
+			WantErr: `invalid frn, "fm:crm:con%41tact:1": invalid character '%' in type at offset 10`,
+		},
+	}
+
+	for label, tc := range testCases {
+		t.Run(label, func(t *testing.T) {
+			c, err := Parse(tc.ID)
+			if tc.WantErr != "" {
+				assert.NotNil(t, err)
+				assert.Equal(t, tc.WantErr, err.Error())
+				return
+			}
+			assert.Nil(t, err)
+
+			assert.Equal(t, ID(tc.ID), c.ID())
+			lineage := ID(tc.ID).Lineage()
+			assert.Equal(t, ID(tc.ID).Base(), lineage[len(lineage)-1])
+		})
+	}
+}
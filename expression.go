@@ -0,0 +1,228 @@
+package frn
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+)
+
+// Expression is a fragment of a DynamoDB expression along with the attribute names and values it references
+type Expression struct {
+	Expression string
+	Names      map[string]*string
+	Values     map[string]*dynamodb.AttributeValue
+}
+
+// IsEmpty returns true if the expression has nothing to apply
+func (e Expression) IsEmpty() bool {
+	return e.Expression == ""
+}
+
+// ApplyUpdate merges the expression into the UpdateExpression of the input, joining clauses of the same action as
+// DynamoDB allows each action once, along with its names and values; placeholders must not collide, see UpdateSets
+// e.g. SET #a = :a ADD #b :b + ADD #c :c => SET #a = :a ADD #b :b, #c :c
+func (e Expression) ApplyUpdate(input *dynamodb.UpdateItemInput) {
+	if e.IsEmpty() {
+		return
+	}
+
+	input.UpdateExpression = mergeUpdate(input.UpdateExpression, e.Expression)
+	input.ExpressionAttributeNames = mergeNames(input.ExpressionAttributeNames, e.Names)
+	input.ExpressionAttributeValues = mergeValues(input.ExpressionAttributeValues, e.Values)
+}
+
+// ApplyQuery sets the expression as the KeyConditionExpression of the input and merges its names and values
+func (e Expression) ApplyQuery(input *dynamodb.QueryInput) {
+	if e.IsEmpty() {
+		return
+	}
+
+	input.KeyConditionExpression = aws.String(e.Expression)
+	input.ExpressionAttributeNames = mergeNames(input.ExpressionAttributeNames, e.Names)
+	input.ExpressionAttributeValues = mergeValues(input.ExpressionAttributeValues, e.Values)
+}
+
+// SetDiff holds the ids added to and removed from a string set attribute
+type SetDiff struct {
+	Attr    string
+	Added   IDSet
+	Removed IDSet
+}
+
+// DiffSet returns the change to the attribute from before to after; see IDSet.Diff
+func DiffSet(attr string, before, after IDSet) SetDiff {
+	added, removed := before.Diff(after)
+	return SetDiff{Attr: attr, Added: added, Removed: removed}
+}
+
+// UpdateSets returns the update expressions that ADD and DELETE the changed ids of each string set attribute, to be
+// applied in order by separate UpdateItem calls. DynamoDB rejects an expression that both ADDs to and DELETEs from the
+// same attribute, so the removals of an attribute that also has additions are deferred to a second expression e.g.
+// ADD #p0 :p0add followed by DELETE #p0 :p0del. Placeholders begin with the alphanumeric prefix, so that expressions from several
+// calls may be applied to the same input. Empty sets, which DynamoDB rejects, are skipped, so no expressions are
+// returned if nothing changed.
+func UpdateSets(prefix string, diffs ...SetDiff) ([]Expression, error) {
+	if prefix == "" || strings.IndexFunc(prefix, func(r rune) bool { return !isAlphanumeric(r) }) != -1 {
+		return nil, fmt.Errorf("invalid frn expression prefix, %q: must be alphanumeric", prefix)
+	}
+
+	var first, second updateBuilder
+	for i, diff := range diffs {
+		added, removed := diff.Added.Trim(), diff.Removed.Trim()
+		if len(added) == 0 && len(removed) == 0 {
+			continue
+		}
+
+		name := fmt.Sprintf("#%v%v", prefix, i)
+		if len(added) > 0 {
+			clause, err := first.put(diff.Attr, name, fmt.Sprintf(":%v%vadd", prefix, i), added)
+			if err != nil {
+				return nil, err
+			}
+			first.adds = append(first.adds, clause)
+		}
+		if len(removed) > 0 {
+			b := &first
+			if len(added) > 0 {
+				b = &second
+			}
+			clause, err := b.put(diff.Attr, name, fmt.Sprintf(":%v%vdel", prefix, i), removed)
+			if err != nil {
+				return nil, err
+			}
+			b.deletes = append(b.deletes, clause)
+		}
+	}
+
+	var exprs []Expression
+	for _, b := range []updateBuilder{first, second} {
+		if e := b.build(); !e.IsEmpty() {
+			exprs = append(exprs, e)
+		}
+	}
+	return exprs, nil
+}
+
+// updateBuilder accumulates the ADD and DELETE clauses of a single update expression
+type updateBuilder struct {
+	adds, deletes []string
+	names         map[string]*string
+	values        map[string]*dynamodb.AttributeValue
+}
+
+// put adds the attribute name and the set value to the expression, returning the clause that references them
+func (b *updateBuilder) put(attr, name, value string, ids IDSet) (string, error) {
+	if b.names == nil {
+		b.names, b.values = map[string]*string{}, map[string]*dynamodb.AttributeValue{}
+	}
+	if err := putSet(b.values, value, ids); err != nil {
+		return "", err
+	}
+	b.names[name] = &attr
+	return name + " " + value, nil
+}
+
+func (b updateBuilder) build() Expression {
+	var clauses []string
+	if len(b.adds) > 0 {
+		clauses = append(clauses, "ADD "+strings.Join(b.adds, ", "))
+	}
+	if len(b.deletes) > 0 {
+		clauses = append(clauses, "DELETE "+strings.Join(b.deletes, ", "))
+	}
+	if len(clauses) == 0 {
+		return Expression{}
+	}
+	return Expression{Expression: strings.Join(clauses, " "), Names: b.names, Values: b.values}
+}
+
+func putSet(values map[string]*dynamodb.AttributeValue, key string, ids IDSet) error {
+	item := &dynamodb.AttributeValue{}
+	if err := ids.MarshalDynamoDBAttributeValue(item); err != nil {
+		return err
+	}
+	values[key] = item
+	return nil
+}
+
+// updateActions are the clause keywords of an update expression; being reserved words, they appear nowhere else
+var updateActions = []string{"SET", "REMOVE", "ADD", "DELETE"}
+
+// mergeUpdate merges the clauses of the update expression, fragment, into expr
+func mergeUpdate(expr *string, fragment string) *string {
+	if expr == nil || *expr == "" {
+		return &fragment
+	}
+
+	clauses := map[string][]string{}
+	for _, s := range []string{*expr, fragment} {
+		var action string
+		for _, field := range strings.Fields(s) {
+			if isUpdateAction(field) {
+				action = strings.ToUpper(field)
+				clauses[action] = append(clauses[action], "")
+				continue
+			}
+			body := clauses[action]
+			if len(body) == 0 {
+				return appendClause(expr, fragment) // not a well formed update expression; leave it to DynamoDB
+			}
+			body[len(body)-1] = strings.TrimSpace(body[len(body)-1] + " " + field)
+		}
+	}
+
+	var merged []string
+	for _, action := range updateActions {
+		if body := clauses[action]; len(body) > 0 {
+			merged = append(merged, action+" "+strings.Join(body, ", "))
+		}
+	}
+	s := strings.Join(merged, " ")
+	return &s
+}
+
+func isUpdateAction(field string) bool {
+	for _, action := range updateActions {
+		if strings.EqualFold(field, action) {
+			return true
+		}
+	}
+	return false
+}
+
+func appendClause(expr *string, fragment string) *string {
+	s := *expr + " " + fragment
+	return &s
+}
+
+func mergeNames(dst, src map[string]*string) map[string]*string {
+	if len(src) == 0 {
+		return dst
+	}
+	if dst == nil {
+		dst = map[string]*string{}
+	}
+	for k, v := range src {
+		dst[k] = v
+	}
+	return dst
+}
+
+func mergeValues(dst, src map[string]*dynamodb.AttributeValue) map[string]*dynamodb.AttributeValue {
+	if len(src) == 0 {
+		return dst
+	}
+	if dst == nil {
+		dst = map[string]*dynamodb.AttributeValue{}
+	}
+	for k, v := range src {
+		dst[k] = v
+	}
+	return dst
+}
+
+func isAlphanumeric(r rune) bool {
+	return r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9'
+}
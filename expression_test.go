@@ -0,0 +1,131 @@
+package frn
+
+import (
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+	"github.com/tj/assert"
+)
+
+func TestUpdateSets(t *testing.T) {
+	testCases := map[string]struct {
+		Diffs      []SetDiff
+		Want       []string
+		WantValues map[string][]string
+	}{
+		"add and delete": {
+			Diffs: []SetDiff{
+				DiffSet("contacts", IDSet{"fm:crm:contact:1", "fm:crm:contact:2"}, IDSet{"fm:crm:contact:2", "fm:crm:contact:3"}),
+			},
+			Want: []string{"ADD #frn0 :frn0add", "DELETE #frn0 :frn0del"},
+			WantValues: map[string][]string{
+				":frn0add": {"fm:crm:contact:3"},
+				":frn0del": {"fm:crm:contact:1"},
+			},
+		},
+		"add only": {
+			Diffs: []SetDiff{
+				DiffSet("contacts", nil, IDSet{"fm:crm:contact:1"}),
+			},
+			Want: []string{"ADD #frn0 :frn0add"},
+			WantValues: map[string][]string{
+				":frn0add": {"fm:crm:contact:1"},
+			},
+		},
+		"multiple attributes": {
+			Diffs: []SetDiff{
+				DiffSet("contacts", IDSet{"fm:crm:contact:1"}, nil),
+				DiffSet("unchanged", IDSet{"fm:crm:contact:1"}, IDSet{"fm:crm:contact:1"}),
+				DiffSet("projects", nil, IDSet{"fm:crm:project:1"}),
+			},
+			Want: []string{"ADD #frn2 :frn2add DELETE #frn0 :frn0del"},
+			WantValues: map[string][]string{
+				":frn0del": {"fm:crm:contact:1"},
+				":frn2add": {"fm:crm:project:1"},
+			},
+		},
+		"multiple attributes - overlapping": {
+			Diffs: []SetDiff{
+				DiffSet("contacts", IDSet{"fm:crm:contact:1"}, IDSet{"fm:crm:contact:2"}),
+				DiffSet("projects", IDSet{"fm:crm:project:1"}, nil),
+			},
+			Want: []string{"ADD #frn0 :frn0add DELETE #frn1 :frn1del", "DELETE #frn0 :frn0del"},
+			WantValues: map[string][]string{
+				":frn0add": {"fm:crm:contact:2"},
+				":frn0del": {"fm:crm:contact:1"},
+				":frn1del": {"fm:crm:project:1"},
+			},
+		},
+		"unchanged": {
+			Diffs: []SetDiff{
+				DiffSet("contacts", IDSet{"fm:crm:contact:1"}, IDSet{"fm:crm:contact:1", ""}),
+			},
+		},
+	}
+
+	for label, tc := range testCases {
+		t.Run(label, func(t *testing.T) {
+			exprs, err := UpdateSets("frn", tc.Diffs...)
+			assert.Nil(t, err)
+
+			var got []string
+			gotValues := map[string][]string{}
+			for _, e := range exprs {
+				assert.False(t, e.IsEmpty())
+				got = append(got, e.Expression)
+				for k, v := range e.Values {
+					gotValues[k] = aws.StringValueSlice(v.SS)
+				}
+			}
+			if tc.WantValues == nil {
+				tc.WantValues = map[string][]string{}
+			}
+			assert.Equal(t, tc.Want, got)
+			assert.Equal(t, tc.WantValues, gotValues)
+		})
+	}
+}
+
+func TestUpdateSets_Prefix(t *testing.T) {
+	diff := DiffSet("contacts", nil, IDSet{"fm:crm:contact:1"})
+
+	exprs, err := UpdateSets("c", diff)
+	assert.Nil(t, err)
+	assert.Equal(t, "ADD #c0 :c0add", exprs[0].Expression)
+	assert.Equal(t, "contacts", aws.StringValue(exprs[0].Names["#c0"]))
+
+	for _, prefix := range []string{"", "c:", "#c"} {
+		_, err := UpdateSets(prefix, diff)
+		assert.NotNil(t, err, prefix)
+	}
+}
+
+func TestExpression_ApplyUpdate(t *testing.T) {
+	contacts, err := UpdateSets("contacts", DiffSet("contacts", nil, IDSet{"fm:crm:contact:1"}))
+	assert.Nil(t, err)
+	projects, err := UpdateSets("projects", DiffSet("projects", nil, IDSet{"fm:crm:project:1"}))
+	assert.Nil(t, err)
+
+	input := &dynamodb.UpdateItemInput{
+		UpdateExpression:         aws.String("SET #name = :name"),
+		ExpressionAttributeNames: map[string]*string{"#name": aws.String("name")},
+		ExpressionAttributeValues: map[string]*dynamodb.AttributeValue{
+			":name": {S: aws.String("blah")},
+		},
+	}
+	contacts[0].ApplyUpdate(input)
+	assert.Equal(t, "SET #name = :name ADD #contacts0 :contacts0add", aws.StringValue(input.UpdateExpression))
+	assert.Equal(t, "contacts", aws.StringValue(input.ExpressionAttributeNames["#contacts0"]))
+	assert.Len(t, input.ExpressionAttributeValues, 2)
+
+	projects[0].ApplyUpdate(input)
+	assert.Equal(t, "SET #name = :name ADD #contacts0 :contacts0add, #projects0 :projects0add", aws.StringValue(input.UpdateExpression))
+	assert.Equal(t, "projects", aws.StringValue(input.ExpressionAttributeNames["#projects0"]))
+	assert.Len(t, input.ExpressionAttributeValues, 3)
+
+	empty := &dynamodb.UpdateItemInput{}
+	Expression{}.ApplyUpdate(empty)
+	assert.Nil(t, empty.UpdateExpression)
+	assert.Nil(t, empty.ExpressionAttributeValues)
+}
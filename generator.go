@@ -0,0 +1,217 @@
+package frn
+
+import (
+	"crypto/rand"
+	"crypto/sha1"
+	"encoding/binary"
+	"encoding/hex"
+	"io"
+	"time"
+
+	"github.com/segmentio/ksuid"
+)
+
+// crockford is the alphabet used to encode ULIDs
+const crockford = "0123456789ABCDEFGHJKMNPQRSTVWXYZ"
+
+// Generator generates the value portion of new ids
+type Generator interface {
+	NewValue() string
+}
+
+// GeneratorFunc adapts a func into a Generator
+type GeneratorFunc func() string
+
+func (fn GeneratorFunc) NewValue() string {
+	return fn()
+}
+
+// source supplies the clock and entropy used by generators
+type source struct {
+	now  func() time.Time
+	rand io.Reader
+}
+
+// GeneratorOption configures the clock or entropy of a Generator
+type GeneratorOption func(*source)
+
+// WithClock replaces time.Now as the clock of the Generator e.g. NewClock for reproducible, time ordered values
+func WithClock(now func() time.Time) GeneratorOption {
+	return func(s *source) {
+		s.now = now
+	}
+}
+
+// WithRand replaces crypto/rand as the entropy of the Generator e.g. NewRand for reproducible values
+func WithRand(r io.Reader) GeneratorOption {
+	return func(s *source) {
+		s.rand = r
+	}
+}
+
+func newSource(opts ...GeneratorOption) source {
+	s := source{
+		now:  time.Now,
+		rand: rand.Reader,
+	}
+	for _, opt := range opts {
+		opt(&s)
+	}
+	return s
+}
+
+// read returns n bytes of entropy; like ksuid.New, it panics if entropy is unavailable
+func (s source) read(n int) []byte {
+	data := make([]byte, n)
+	if _, err := io.ReadFull(s.rand, data); err != nil {
+		panic(err)
+	}
+	return data
+}
+
+type ksuidGenerator struct {
+	source
+}
+
+// NewGenerator returns a Generator of KSUIDs configured with opts; with no options it is the same as NewKSUIDGenerator
+//
+//	g := NewGenerator(WithClock(NewClock(start, time.Second)), WithRand(NewRand(1)))
+func NewGenerator(opts ...GeneratorOption) Generator {
+	return NewKSUIDGenerator(opts...)
+}
+
+// NewKSUIDGenerator returns a Generator of KSUIDs, the default for NewValue and IDFactoryFunc.NewID
+func NewKSUIDGenerator(opts ...GeneratorOption) Generator {
+	return ksuidGenerator{source: newSource(opts...)}
+}
+
+func (g ksuidGenerator) NewValue() string {
+	k, err := ksuid.FromParts(g.now(), g.read(16))
+	if err != nil {
+		panic(err)
+	}
+	return k.String()
+}
+
+type uuidV4Generator struct {
+	source
+}
+
+// NewUUIDv4Generator returns a Generator of random UUIDs e.g. 0b0f3e0e-7a3b-4c1e-9e2a-1d9f0c3b7a11
+func NewUUIDv4Generator(opts ...GeneratorOption) Generator {
+	return uuidV4Generator{source: newSource(opts...)}
+}
+
+func (g uuidV4Generator) NewValue() string {
+	return formatUUID(g.read(16), 4)
+}
+
+type uuidV7Generator struct {
+	source
+}
+
+// NewUUIDv7Generator returns a Generator of time ordered UUIDs, prefixed by the unix time in milliseconds
+func NewUUIDv7Generator(opts ...GeneratorOption) Generator {
+	return uuidV7Generator{source: newSource(opts...)}
+}
+
+func (g uuidV7Generator) NewValue() string {
+	data := make([]byte, 16)
+	putMillis(data, g.now())
+	copy(data[6:], g.read(10))
+	return formatUUID(data, 7)
+}
+
+type ulidGenerator struct {
+	source
+}
+
+// NewULIDGenerator returns a Generator of time ordered ULIDs e.g. 01ARZ3NDEKTSV4RRFFQ69G5FAV
+func NewULIDGenerator(opts ...GeneratorOption) Generator {
+	return ulidGenerator{source: newSource(opts...)}
+}
+
+func (g ulidGenerator) NewValue() string {
+	data := make([]byte, 16)
+	putMillis(data, g.now())
+	copy(data[6:], g.read(10))
+
+	// encode 128 bits as 26 characters of 5 bits each, the leading 2 bits being zero
+	var (
+		buf = make([]byte, 26)
+		hi  = binary.BigEndian.Uint64(data[:8])
+		lo  = binary.BigEndian.Uint64(data[8:])
+	)
+	for i := len(buf) - 1; i >= 0; i-- {
+		buf[i] = crockford[lo&0x1f]
+		lo = lo>>5 | hi<<59
+		hi >>= 5
+	}
+	return string(buf)
+}
+
+// HashGenerator generates deterministic, name based values by hashing a natural key within a space, similar to UUIDv5.
+// The same space and name always produce the same value, which makes imports idempotent.
+type HashGenerator struct {
+	space [sha1.Size]byte
+}
+
+// NewHashGenerator returns a HashGenerator whose values are unique to the space e.g. the source system of the keys
+func NewHashGenerator(space string) HashGenerator {
+	return HashGenerator{space: sha1.Sum([]byte(space))}
+}
+
+// ValueOf returns the value for the name e.g. ids may be created via factory.WithValue(g.ValueOf(key))
+func (g HashGenerator) ValueOf(name string) string {
+	h := sha1.New()
+	h.Write(g.space[:16])
+	h.Write([]byte(name))
+	return formatUUID(h.Sum(nil)[:16], 5)
+}
+
+// For returns a Generator that always returns the value for the name
+func (g HashGenerator) For(name string) Generator {
+	v := g.ValueOf(name)
+	return GeneratorFunc(func() string { return v })
+}
+
+// generatorFactory is an IDFactory that generates values with a Generator
+type generatorFactory struct {
+	fn        IDFactoryFunc
+	generator Generator
+}
+
+func (f generatorFactory) NewID() ID {
+	return f.fn(f.generator.NewValue())
+}
+
+func (f generatorFactory) WithValue(v string) ID {
+	return f.fn(v)
+}
+
+// putMillis writes the unix time in milliseconds into the first 6 bytes of data
+func putMillis(data []byte, t time.Time) {
+	ms := uint64(t.UnixMilli())
+	for i := 5; i >= 0; i-- {
+		data[i] = byte(ms)
+		ms >>= 8
+	}
+}
+
+// formatUUID sets the version and variant bits of data, 16 bytes, and formats it as a UUID
+func formatUUID(data []byte, version byte) string {
+	data[6] = data[6]&0x0f | version<<4
+	data[8] = data[8]&0x3f | 0x80
+
+	buf := make([]byte, 36)
+	hex.Encode(buf[0:8], data[0:4])
+	buf[8] = '-'
+	hex.Encode(buf[9:13], data[4:6])
+	buf[13] = '-'
+	hex.Encode(buf[14:18], data[6:8])
+	buf[18] = '-'
+	hex.Encode(buf[19:23], data[8:10])
+	buf[23] = '-'
+	hex.Encode(buf[24:], data[10:])
+	return string(buf)
+}
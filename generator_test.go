@@ -0,0 +1,128 @@
+package frn
+
+import (
+	"bytes"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/segmentio/ksuid"
+	"github.com/tj/assert"
+)
+
+func TestGenerators(t *testing.T) {
+	testCases := map[string]struct {
+		Generator Generator
+		Want      *regexp.Regexp
+	}{
+		"ksuid": {
+			Generator: NewKSUIDGenerator(),
+			Want:      regexp.MustCompile(`^[0-9a-zA-Z]{27}$`),
+		},
+		"uuid v4": {
+			Generator: NewUUIDv4Generator(),
+			Want:      regexp.MustCompile(`^[0-9a-f]{8}-[0-9a-f]{4}-4[0-9a-f]{3}-[89ab][0-9a-f]{3}-[0-9a-f]{12}$`),
+		},
+		"uuid v7": {
+			Generator: NewUUIDv7Generator(),
+			Want:      regexp.MustCompile(`^[0-9a-f]{8}-[0-9a-f]{4}-7[0-9a-f]{3}-[89ab][0-9a-f]{3}-[0-9a-f]{12}$`),
+		},
+		"ulid": {
+			Generator: NewULIDGenerator(),
+			Want:      regexp.MustCompile(`^[0-7][0-9A-HJKMNP-TV-Z]{25}$`),
+		},
+		"hash": {
+			Generator: NewHashGenerator("vendor").For("abc"),
+			Want:      regexp.MustCompile(`^[0-9a-f]{8}-[0-9a-f]{4}-5[0-9a-f]{3}-[89ab][0-9a-f]{3}-[0-9a-f]{12}$`),
+		},
+	}
+
+	ns := NewNamespace("", ServiceCRM)
+	for label, tc := range testCases {
+		t.Run(label, func(t *testing.T) {
+			v := tc.Generator.NewValue()
+			assert.Regexp(t, tc.Want, v)
+
+			id := ns.IDFactoryWithGenerator(TypeProject, tc.Generator).NewID()
+			assert.True(t, id.IsValid())
+		})
+	}
+}
+
+func TestGenerators_TimeOrdered(t *testing.T) {
+	var (
+		start   = time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+		entropy = bytes.Repeat([]byte{0xff, 0x00}, 1024)
+	)
+
+	testCases := map[string]func(opts ...GeneratorOption) Generator{
+		"ksuid":   NewKSUIDGenerator,
+		"uuid v7": NewUUIDv7Generator,
+		"ulid":    NewULIDGenerator,
+	}
+
+	for label, fn := range testCases {
+		t.Run(label, func(t *testing.T) {
+			g := fn(WithClock(NewClock(start, time.Second)), WithRand(bytes.NewReader(entropy)))
+			a, b := g.NewValue(), g.NewValue()
+			assert.True(t, a < b, "%v < %v", a, b)
+		})
+	}
+}
+
+func TestNewGenerator(t *testing.T) {
+	var (
+		start = time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+		newFn = func() Generator {
+			return NewGenerator(WithClock(NewClock(start, time.Second)), WithRand(NewRand(1)))
+		}
+		a, b = newFn(), newFn()
+		ns   = NewNamespace("", ServiceCRM)
+	)
+
+	var prev ID
+	for i := 0; i < 3; i++ {
+		id := ns.IDFactoryWithGenerator(TypeProject, a).NewID()
+		assert.Equal(t, id, ns.IDFactoryWithGenerator(TypeProject, b).NewID())
+		assert.True(t, id.IsValid())
+		assert.True(t, prev < id, "%v < %v", prev, id)
+
+		createdAt, ok := id.CreatedAt()
+		assert.True(t, ok)
+		assert.True(t, start.Add(time.Duration(i)*time.Second).Equal(createdAt))
+		prev = id
+	}
+}
+
+func TestULIDGenerator(t *testing.T) {
+	g := NewULIDGenerator(
+		WithClock(func() time.Time { return time.UnixMilli(1469918176385) }),
+		WithRand(bytes.NewReader(make([]byte, 10))),
+	)
+	assert.Equal(t, "01ARYZ6S410000000000000000", g.NewValue())
+}
+
+func TestHashGenerator(t *testing.T) {
+	var (
+		a = NewHashGenerator("vendor")
+		b = NewHashGenerator("other")
+	)
+
+	assert.Equal(t, a.ValueOf("abc"), a.ValueOf("abc"))
+	assert.NotEqual(t, a.ValueOf("abc"), a.ValueOf("abd"))
+	assert.NotEqual(t, a.ValueOf("abc"), b.ValueOf("abc"))
+}
+
+func TestIDFactoryFunc_WithGenerator(t *testing.T) {
+	var (
+		seq     = NewSequence(0)
+		factory = NewNamespace("", ServiceCRM).IDFactory(TypeProject).WithGenerator(GeneratorFunc(seq.Next))
+	)
+
+	assert.Equal(t, ID("fm:crm:project:1"), factory.NewID())
+	assert.Equal(t, ID("fm:crm:project:2"), factory.NewID())
+	assert.Equal(t, ID("fm:crm:project:abc"), factory.WithValue("abc"))
+
+	_, err := ksuid.Parse(NewValue())
+	assert.Nil(t, err)
+}
@@ -0,0 +1,108 @@
+package frn
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Glob matches ids segment by segment e.g. fm:*:project:123:contract:*, fm:fin:entity:*/account/*.
+// Segments are separated by : or / and the separator must match; * matches exactly one segment and ** as the final
+// segment matches the remaining hierarchy, if any, e.g. fm:crm:project:123:** matches the project, its children and paths.
+// Values within a glob are compared in their escaped form; see EscapeValue.
+type Glob struct {
+	raw      string
+	segments []globSegment
+}
+
+// globSegment is a segment of a glob or id with the separator that precedes it; sep is blank for the first segment
+type globSegment struct {
+	sep  byte
+	text string
+}
+
+// CompileGlob parses s into a Glob
+func CompileGlob(s string) (Glob, error) {
+	if s == "" {
+		return Glob{}, fmt.Errorf("invalid frn glob, %q: empty glob", s)
+	}
+
+	segments := splitSegments(s)
+	for i, seg := range segments {
+		switch {
+		case seg.text == "":
+			return Glob{}, fmt.Errorf("invalid frn glob, %q: empty segment at %v", s, i)
+		case seg.text == anyShape && i != len(segments)-1:
+			return Glob{}, fmt.Errorf("invalid frn glob, %q: %v must be the final segment", s, anyShape)
+		case strings.Contains(seg.text, wildcard) && seg.text != wildcard && seg.text != anyShape:
+			return Glob{}, fmt.Errorf("invalid frn glob, %q: %v must be the entire segment", s, wildcard)
+		}
+	}
+
+	return Glob{raw: s, segments: segments}, nil
+}
+
+// MustCompileGlob is like CompileGlob, but panics if s cannot be compiled
+func MustCompileGlob(s string) Glob {
+	g, err := CompileGlob(s)
+	if err != nil {
+		panic(err)
+	}
+	return g
+}
+
+// Match returns true if the id is valid and matches the glob
+func (g Glob) Match(id ID) bool {
+	if len(g.segments) == 0 || !id.IsValid() {
+		return false
+	}
+
+	got := splitSegments(id.String())
+	for i, want := range g.segments {
+		switch {
+		case want.text == anyShape:
+			return true
+		case i >= len(got):
+			return false
+		case want.sep != got[i].sep:
+			return false
+		case want.text != wildcard && want.text != got[i].text:
+			return false
+		}
+	}
+	return len(got) == len(g.segments)
+}
+
+func (g Glob) String() string {
+	return g.raw
+}
+
+func (g Glob) MarshalText() ([]byte, error) {
+	return []byte(g.raw), nil
+}
+
+func (g *Glob) UnmarshalText(data []byte) error {
+	v, err := CompileGlob(string(data))
+	if err != nil {
+		return err
+	}
+	*g = v
+	return nil
+}
+
+// splitSegments splits s on : and /, retaining the separator that precedes each segment
+func splitSegments(s string) []globSegment {
+	var (
+		segments []globSegment
+		seg      globSegment
+		start    int
+	)
+	for i := 0; i < len(s); i++ {
+		if c := s[i]; c == sep[0] || c == pathSep[0] {
+			seg.text = s[start:i]
+			segments = append(segments, seg)
+			seg, start = globSegment{sep: c}, i+1
+		}
+	}
+	seg.text = s[start:]
+	return append(segments, seg)
+}
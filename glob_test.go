@@ -0,0 +1,146 @@
+package frn
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/tj/assert"
+)
+
+func TestGlob_Match(t *testing.T) {
+	testCases := map[string]struct {
+		Glob string
+		ID   ID
+		Want bool
+	}{
+		"exact": {
+			Glob: "fm:crm:project:123",
+			ID:   "fm:crm:project:123",
+			Want: true,
+		},
+		"exact - mismatch": {
+			Glob: "fm:crm:project:123",
+			ID:   "fm:crm:project:1234",
+			Want: false,
+		},
+		"value wildcard": {
+			Glob: "fm:crm:project:*",
+			ID:   "fm:crm:project:123",
+			Want: true,
+		},
+		"value wildcard - child": {
+			Glob: "fm:crm:project:*",
+			ID:   "fm:crm:project:123:contract:456",
+			Want: false,
+		},
+		"value wildcard - path": {
+			Glob: "fm:crm:project:*",
+			ID:   "fm:crm:project:123/account",
+			Want: false,
+		},
+		"service wildcard": {
+			Glob: "fm:*:project:123:contract:*",
+			ID:   "fm:fin:project:123:contract:456",
+			Want: true,
+		},
+		"service wildcard - parent mismatch": {
+			Glob: "fm:*:project:123:contract:*",
+			ID:   "fm:fin:project:124:contract:456",
+			Want: false,
+		},
+		"path wildcard": {
+			Glob: "fm:fin:entity:*/account/*",
+			ID:   "fm:fin:entity:1/account/ar",
+			Want: true,
+		},
+		"path wildcard - separator mismatch": {
+			Glob: "fm:fin:entity:*/account/*",
+			ID:   "fm:fin:entity:1:account:ar",
+			Want: false,
+		},
+		"path wildcard - deeper path": {
+			Glob: "fm:fin:entity:*/account/*",
+			ID:   "fm:fin:entity:1/account/ar/2024",
+			Want: false,
+		},
+		"hierarchy - self": {
+			Glob: "fm:crm:project:123:**",
+			ID:   "fm:crm:project:123",
+			Want: true,
+		},
+		"hierarchy - child": {
+			Glob: "fm:crm:project:123:**",
+			ID:   "fm:crm:project:123:contract:456:change:789",
+			Want: true,
+		},
+		"hierarchy - path": {
+			Glob: "fm:crm:project:123:**",
+			ID:   "fm:crm:project:123/account/ar",
+			Want: true,
+		},
+		"hierarchy - sibling": {
+			Glob: "fm:crm:project:123:**",
+			ID:   "fm:crm:project:1234:contract:456",
+			Want: false,
+		},
+		"any": {
+			Glob: "**",
+			ID:   "fm:crm:project:123",
+			Want: true,
+		},
+		"invalid id": {
+			Glob: "**",
+			ID:   "fm:crm:project",
+			Want: false,
+		},
+		"empty id": {
+			Glob: "fm:*:*:*",
+			ID:   "",
+			Want: false,
+		},
+	}
+
+	for label, tc := range testCases {
+		t.Run(label, func(t *testing.T) {
+			g, err := CompileGlob(tc.Glob)
+			assert.Nil(t, err)
+			assert.Equal(t, tc.Want, g.Match(tc.ID))
+		})
+	}
+}
+
+func TestCompileGlob(t *testing.T) {
+	testCases := map[string]struct {
+		Glob    string
+		WantErr bool
+	}{
+		"ok":               {Glob: "fm:crm:project:*"},
+		"empty":            {Glob: "", WantErr: true},
+		"empty segment":    {Glob: "fm::project:*", WantErr: true},
+		"hierarchy middle": {Glob: "fm:**:project:*", WantErr: true},
+		"partial wildcard": {Glob: "fm:crm:proj*:1", WantErr: true},
+	}
+
+	for label, tc := range testCases {
+		t.Run(label, func(t *testing.T) {
+			_, err := CompileGlob(tc.Glob)
+			assert.Equal(t, tc.WantErr, err != nil, "%v", err)
+		})
+	}
+}
+
+func TestGlob_JSON(t *testing.T) {
+	var v struct {
+		Resources []Glob
+	}
+	err := json.Unmarshal([]byte(`{"Resources":["fm:crm:project:*"]}`), &v)
+	assert.Nil(t, err)
+	assert.True(t, v.Resources[0].Match("fm:crm:project:1"))
+
+	data, err := json.Marshal(v)
+	assert.Nil(t, err)
+	assert.Equal(t, `{"Resources":["fm:crm:project:*"]}`, string(data))
+
+	err = json.Unmarshal([]byte(`{"Resources":["fm:**:project"]}`), &v)
+	assert.NotNil(t, err)
+}
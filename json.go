@@ -0,0 +1,127 @@
+package frn
+
+import (
+	"encoding/json"
+	"strings"
+)
+
+// setSep separates ids within the text form of IDSet and KeySet
+const setSep = ","
+
+// toIDSet converts ss into a set with blank ids removed
+func toIDSet(ss []string) IDSet {
+	var ids IDSet
+	for _, s := range ss {
+		ids = append(ids, ID(s))
+	}
+	return ids.Trim()
+}
+
+// splitText splits the text form of a set into its ids
+func splitText(data []byte) []string {
+	if len(data) == 0 {
+		return nil
+	}
+	return strings.Split(string(data), setSep)
+}
+
+func (id ID) MarshalJSON() ([]byte, error) {
+	return json.Marshal(string(id))
+}
+
+func (id ID) MarshalText() ([]byte, error) {
+	return []byte(id), nil
+}
+
+// UnmarshalJSON decodes a json string into the id; null decodes to an empty id
+func (id *ID) UnmarshalJSON(data []byte) error {
+	var s *string
+	if err := json.Unmarshal(data, &s); err != nil {
+		return err
+	}
+	if s == nil {
+		*id = ""
+		return nil
+	}
+
+	*id = ID(*s)
+
+	return nil
+}
+
+func (id *ID) UnmarshalText(data []byte) error {
+	*id = ID(data)
+
+	return nil
+}
+
+// MarshalJSON encodes the set as an array of ids with blank ids removed; empty sets encode as null
+//
+//goland:noinspection GoMixedReceiverTypes
+func (vv IDSet) MarshalJSON() ([]byte, error) {
+	idSet := vv.Trim()
+	if len(idSet) == 0 {
+		return []byte("null"), nil
+	}
+	return json.Marshal([]ID(idSet))
+}
+
+// MarshalText encodes the set as comma separated ids with blank ids removed
+//
+//goland:noinspection GoMixedReceiverTypes
+func (vv IDSet) MarshalText() ([]byte, error) {
+	return []byte(strings.Join(vv.Trim().Strings(), setSep)), nil
+}
+
+// UnmarshalJSON decodes an array of ids into the set with blank ids removed; null and [] decode to a nil set
+//
+//goland:noinspection GoMixedReceiverTypes
+func (vv *IDSet) UnmarshalJSON(data []byte) error {
+	var ss []string
+	if err := json.Unmarshal(data, &ss); err != nil {
+		return err
+	}
+
+	*vv = toIDSet(ss)
+
+	return nil
+}
+
+//goland:noinspection GoMixedReceiverTypes
+func (vv *IDSet) UnmarshalText(data []byte) error {
+	*vv = toIDSet(splitText(data))
+
+	return nil
+}
+
+// MarshalJSON encodes the set as an array of unique ids with blank ids removed; empty sets encode as null
+func (kk KeySet) MarshalJSON() ([]byte, error) {
+	keySet := NewKeySet(kk.Strings()...)
+	if len(keySet) == 0 {
+		return []byte("null"), nil
+	}
+	return json.Marshal([]ID(keySet))
+}
+
+// MarshalText encodes the set as comma separated unique ids with blank ids removed
+func (kk KeySet) MarshalText() ([]byte, error) {
+	return []byte(strings.Join(NewKeySet(kk.Strings()...).Strings(), setSep)), nil
+}
+
+// UnmarshalJSON decodes an array of ids into the set with blank and duplicate ids removed; null and [] decode to a nil set
+func (kk *KeySet) UnmarshalJSON(data []byte) error {
+	var ss []string
+	if err := json.Unmarshal(data, &ss); err != nil {
+		return err
+	}
+
+	*kk = NewKeySet(ss...)
+
+	return nil
+}
+
+func (kk *KeySet) UnmarshalText(data []byte) error {
+	*kk = NewKeySet(splitText(data)...)
+
+	return nil
+}
@@ -0,0 +1,206 @@
+package frn
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/tj/assert"
+)
+
+func TestID_MarshalJSON(t *testing.T) {
+	type Example struct {
+		ID  ID         `json:"id"`
+		Ptr *ID        `json:"ptr,omitempty"`
+		Map map[ID]int `json:"map,omitempty"`
+	}
+
+	want := Example{
+		ID:  "fm:crm:project:1",
+		Ptr: Ptr("fm:crm:project:2"),
+		Map: map[ID]int{"fm:crm:project:3": 3},
+	}
+	data, err := json.Marshal(want)
+	assert.Nil(t, err)
+	assert.Equal(t, `{"id":"fm:crm:project:1","ptr":"fm:crm:project:2","map":{"fm:crm:project:3":3}}`, string(data))
+
+	var got Example
+	err = json.Unmarshal(data, &got)
+	assert.Nil(t, err)
+	assert.Equal(t, want, got)
+}
+
+func TestID_UnmarshalJSON(t *testing.T) {
+	testCases := map[string]struct {
+		Data       string
+		Want       ID
+		WantErr    bool
+		WantErrMsg string
+	}{
+		"ok": {
+			Data: `"fm:crm:project:1"`,
+			Want: "fm:crm:project:1",
+		},
+		"null": {
+			Data: `null`,
+			Want: "",
+		},
+		"invalid": {
+			Data: `"blah"`,
+			Want: "blah",
+		},
+		"not a string": {
+			Data:    `123`,
+			WantErr: true,
+		},
+	}
+
+	for label, tc := range testCases {
+		t.Run(label, func(t *testing.T) {
+			got := ID("initial")
+			err := json.Unmarshal([]byte(tc.Data), &got)
+			if tc.WantErr {
+				assert.NotNil(t, err)
+				if tc.WantErrMsg != "" {
+					assert.EqualError(t, err, tc.WantErrMsg)
+				}
+				return
+			}
+			assert.Nil(t, err)
+			assert.Equal(t, tc.Want, got)
+		})
+	}
+}
+
+func TestIDSet_MarshalJSON(t *testing.T) {
+	testCases := map[string]struct {
+		Set      IDSet
+		WantData string
+		Want     IDSet
+	}{
+		"ok": {
+			Set:      IDSet{"a", "b", "c"},
+			WantData: `["a","b","c"]`,
+			Want:     IDSet{"a", "b", "c"},
+		},
+		"nil": {
+			Set:      nil,
+			WantData: `null`,
+			Want:     nil,
+		},
+		"empty": {
+			Set:      IDSet{},
+			WantData: `null`,
+			Want:     nil,
+		},
+		"removes empty strings": {
+			Set:      IDSet{"a", ""},
+			WantData: `["a"]`,
+			Want:     IDSet{"a"},
+		},
+	}
+
+	for label, tc := range testCases {
+		t.Run(label, func(t *testing.T) {
+			data, err := json.Marshal(tc.Set)
+			assert.Nil(t, err)
+			assert.Equal(t, tc.WantData, string(data))
+
+			var got IDSet
+			err = json.Unmarshal(data, &got)
+			assert.Nil(t, err)
+			assert.Equal(t, tc.Want, got)
+		})
+	}
+}
+
+func TestKeySet_MarshalJSON(t *testing.T) {
+	testCases := map[string]struct {
+		Set      KeySet
+		WantData string
+		Want     KeySet
+	}{
+		"ok": {
+			Set:      KeySet{"a", "b"},
+			WantData: `["a","b"]`,
+			Want:     KeySet{"a", "b"},
+		},
+		"nil": {
+			Set:      nil,
+			WantData: `null`,
+			Want:     nil,
+		},
+		"removes duplicates and empty strings": {
+			Set:      KeySet{"a", "", "b", "a"},
+			WantData: `["a","b"]`,
+			Want:     KeySet{"a", "b"},
+		},
+	}
+
+	for label, tc := range testCases {
+		t.Run(label, func(t *testing.T) {
+			data, err := json.Marshal(tc.Set)
+			assert.Nil(t, err)
+			assert.Equal(t, tc.WantData, string(data))
+
+			var got KeySet
+			err = json.Unmarshal(data, &got)
+			assert.Nil(t, err)
+			assert.Equal(t, tc.Want, got)
+		})
+	}
+}
+
+func TestSets_UnmarshalJSON(t *testing.T) {
+	testCases := map[string]struct {
+		Data       string
+		WantIDSet  IDSet
+		WantKeySet KeySet
+		WantErr    bool
+	}{
+		"empty array": {
+			Data: `[]`,
+		},
+		"duplicates": {
+			Data:       `["a","a"]`,
+			WantIDSet:  IDSet{"a", "a"},
+			WantKeySet: KeySet{"a"},
+		},
+		"blank": {
+			Data:       `["fm:crm:project:1",""]`,
+			WantIDSet:  IDSet{"fm:crm:project:1"},
+			WantKeySet: KeySet{"fm:crm:project:1"},
+		},
+	}
+
+	for label, tc := range testCases {
+		t.Run(label, func(t *testing.T) {
+			var idSet IDSet
+			err := json.Unmarshal([]byte(tc.Data), &idSet)
+			assert.Equal(t, tc.WantErr, err != nil)
+			assert.Equal(t, tc.WantIDSet, idSet)
+
+			var keySet KeySet
+			err = json.Unmarshal([]byte(tc.Data), &keySet)
+			assert.Equal(t, tc.WantErr, err != nil)
+			assert.Equal(t, tc.WantKeySet, keySet)
+		})
+	}
+}
+
+func TestSets_MarshalText(t *testing.T) {
+	idSet := IDSet{"fm:crm:project:1", "", "fm:crm:project:2"}
+	data, err := idSet.MarshalText()
+	assert.Nil(t, err)
+	assert.Equal(t, "fm:crm:project:1,fm:crm:project:2", string(data))
+
+	var gotIDSet IDSet
+	assert.Nil(t, gotIDSet.UnmarshalText(data))
+	assert.Equal(t, IDSet{"fm:crm:project:1", "fm:crm:project:2"}, gotIDSet)
+
+	var gotKeySet KeySet
+	assert.Nil(t, gotKeySet.UnmarshalText([]byte("a,b,a")))
+	assert.Equal(t, KeySet{"a", "b"}, gotKeySet)
+
+	assert.Nil(t, gotKeySet.UnmarshalText(nil))
+	assert.Nil(t, gotKeySet)
+}
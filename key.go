@@ -3,13 +3,10 @@ package frn
 import (
 	"bytes"
 	"fmt"
-	"regexp"
 	"strings"
 
 	"github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/service/dynamodb"
-
-	"github.com/segmentio/ksuid"
 )
 
 const (
@@ -17,22 +14,21 @@ const (
 	pathSep = "/" // separates parent and child ids from pathSep data
 )
 
-var (
-	// see test case, TestID_IsValid, for examples
-	// e.g. fm:crm:contact:1234
-	reValid = regexp.MustCompile(`^([a-zA-Z0-9\-_]+:){3}[a-zA-Z0-9\-_]+(:[a-zA-Z0-9\-_]+:[a-zA-Z0-9\-_]*)?(/[a-z0-9\-_/]+)?$`)
-)
-
 type IDFactoryFunc func(v string) ID
 
 func (fn IDFactoryFunc) NewID() ID {
-	return fn(ksuid.New().String())
+	return fn(NewValue())
 }
 
 func (fn IDFactoryFunc) WithValue(v string) ID {
 	return fn(v)
 }
 
+// WithGenerator returns a factory whose NewID generates values with the generator rather than KSUID
+func (fn IDFactoryFunc) WithGenerator(g Generator) IDFactory {
+	return generatorFactory{fn: fn, generator: g}
+}
+
 type IDFactory interface {
 	NewID() ID
 	WithValue(v string) ID
@@ -58,16 +54,22 @@ func (n Namespace) Env() string {
 
 func (n Namespace) IDFactory(t Type) IDFactoryFunc {
 	return func(v string) ID {
-		return ID(n.String() + sep + t.String() + sep + v)
+		return n.New(t, v)
 	}
 }
 
+// IDFactoryWithGenerator returns a factory for the type whose NewID generates values with the generator
+func (n Namespace) IDFactoryWithGenerator(t Type, g Generator) IDFactory {
+	return n.IDFactory(t).WithGenerator(g)
+}
+
+// New returns the id of the type with the value, escaping any reserved characters in the value; see EscapeValue
 func (n Namespace) New(t Type, id string) ID {
-	return ID(n.String() + sep + t.String() + sep + id)
+	return ID(n.String() + sep + t.String() + sep + EscapeValue(id))
 }
 
 func (n Namespace) NewWithChild(t Type, id string, st Type, idSub string) ID {
-	return ID(n.String() + sep + t.String() + sep + id + sep + st.String() + sep + idSub)
+	return n.New(t, id).Sub(st, idSub)
 }
 
 func (n Namespace) String() string {
@@ -76,35 +78,10 @@ func (n Namespace) String() string {
 
 type ID string
 
-func (id ID) part(i int) (begin, end int, ok bool) {
-	for index, s := range id {
-		if s != ':' {
-			continue
-		}
-
-		switch i {
-		case 0:
-			return begin, index, true
-		default:
-			begin = index + 1
-		}
-		i--
-	}
-
-	if i == 0 {
-		return begin, len(id), true
-	}
-
-	return 0, 0, false
-}
-
-func (id ID) partString(i int) (string, bool) {
-	begin, end, ok := id.part(i)
-	if !ok {
-		return "", false
-	}
-
-	return string(id[begin:end]), true
+// components decomposes the id on a best effort basis; see Parse
+func (id ID) components() Components {
+	c, _ := parse(id.String())
+	return c
 }
 
 // Base returns the id sans any path elements e.g. fm:crm:contact:1234/key/value => fm:crm:contact:1234
@@ -115,36 +92,44 @@ func (id ID) Base() ID {
 	return id
 }
 
+// Child returns the innermost child as a standalone id e.g. fm:crm:project:1:contract:2:approval:3 => fm:crm:approval:3
+// The child is sliced from the id, rather than escaped anew, so that it keeps the spelling of the id
 func (id ID) Child() ID {
-	st, ok := id.partString(4)
-	if !ok {
+	lineage := id.Lineage()
+	if len(lineage) < 2 {
 		return ""
 	}
 
-	si, ok := id.partString(5)
-	if !ok {
-		return ""
-	}
+	parent, child := lineage[len(lineage)-2], lineage[len(lineage)-1]
+	return ID(id.Namespace().String() + sep + child.String()[len(parent)+len(sep):])
+}
 
-	// strip trailing path from child
-	if index := strings.Index(si, pathSep); index != -1 {
-		si = si[:index]
+// Ancestors returns the lineage of the id excluding the id itself e.g.
+// fm:crm:project:1:contract:2:approval:3 => [fm:crm:project:1, fm:crm:project:1:contract:2]
+func (id ID) Ancestors() []ID {
+	lineage := id.Lineage()
+	if len(lineage) < 2 {
+		return nil
 	}
-
-	return id.Namespace().New(Type(st), si)
+	return lineage[:len(lineage)-1]
 }
 
-// ChildPrefix returns prefix all children of parent must begin with
+// ChildPrefix returns prefix all children of parent must begin with; for a child id, the parent is its immediate
+// parent e.g. fm:crm:project:1:contract:2:approval:3 => fm:crm:project:1:contract:2:
 func (id ID) ChildPrefix() string {
 	if id.HasChild() {
-		return id.Parent().ChildPrefix()
+		return id.Parent().String() + sep
 	}
 	return id.String() + sep
 }
 
+// Depth returns the number of children nested within the id e.g. fm:crm:project:1 => 0, fm:crm:project:1:contract:2 => 1
+func (id ID) Depth() int {
+	return len(id.components().Children)
+}
+
 func (id ID) HasChild() bool {
-	_, ok := id.partString(4)
-	return ok
+	return len(id.components().Children) > 0
 }
 
 func (id ID) HasPath() bool {
@@ -167,11 +152,7 @@ func (id ID) In(wants ...ID) bool {
 }
 
 func (id ID) Value() string {
-	s, _ := id.partString(3)
-	if index := strings.Index(s, pathSep); index != -1 {
-		return s[:index]
-	}
-	return s
+	return id.components().Value
 }
 
 func (id ID) IsEmpty() bool {
@@ -192,8 +173,30 @@ func (id ID) IsPresent() bool {
 	return !id.IsEmpty()
 }
 
+// IsValid returns true if the id can be parsed; use Parse to find out why an id is invalid
 func (id ID) IsValid() bool {
-	return reValid.MatchString(id.String())
+	_, err := Parse(id.String())
+	return err == nil
+}
+
+// Lineage returns the id, sans path, preceded by each of its ancestors e.g.
+// fm:crm:project:1:contract:2/key/value => [fm:crm:project:1, fm:crm:project:1:contract:2]
+// Ancestors are prefixes of the id, so ids written before values were escaped keep their spelling
+func (id ID) Lineage() []ID {
+	var (
+		base    = id.Base()
+		lineage []ID
+		parts   int
+	)
+	for i := 0; i <= len(base); i++ {
+		if i < len(base) && base[i] != sep[0] {
+			continue
+		}
+		if parts++; parts >= 4 && parts%2 == 0 {
+			lineage = append(lineage, base[:i])
+		}
+	}
+	return lineage
 }
 
 func (id ID) Namespace() Namespace {
@@ -211,51 +214,40 @@ func (id ID) Namespace() Namespace {
 	return Namespace(id[:a+b+1])
 }
 
+// Parent returns the immediate parent of a child id e.g. fm:crm:project:1:contract:2:approval:3 => fm:crm:project:1:contract:2
+// ids without children return themselves sans path
 func (id ID) Parent() ID {
-	if id.HasChild() {
-		return id.Namespace().New(id.Type(), id.Value())
+	if ancestors := id.Ancestors(); len(ancestors) > 0 {
+		return ancestors[len(ancestors)-1]
 	}
-
-	// strip path if present from parent
-	if index := strings.Index(id.String(), pathSep); index != -1 {
-		return id[0:index]
-	}
-
-	return id
+	return id.Base()
 }
 
-// Path extracts the tertiary values from the id
+// Path extracts the tertiary values from the id; head and tail are unescaped, so a segment holding / cannot be told
+// apart from two segments in tail e.g. fm:fin:entity:1/account/a%2fb/c => account, a/b/c
+// Use PathSegments for lossless access to the segments
 func (id ID) Path() (head, tail string, ok bool) {
-	s := id.String()
-	index := strings.Index(s, pathSep)
-	if index == -1 {
+	path := id.components().Path
+	if len(path) == 0 {
 		return "", "", false
 	}
 
-	parts := strings.SplitN(s[index+1:], pathSep, 2)
-	if len(parts) < 2 {
-		if parts[0] == "" {
-			return "", "", false
-		}
-		return parts[0], "", true
-	}
-
-	return parts[0], parts[1], true
+	return path[0], strings.Join(path[1:], pathSep), true
 }
 
 func (id ID) Service() Service {
-	partString, _ := id.partString(1)
-	return Service(partString)
+	return id.components().Service
 }
 
 // Shape returns the shape of the id e.g., frm:crm:project:1234 => project, frm:crm:entity:1:card_tx:2/fund_request/3 => entity/card_tx#fund_request
-// Note: only first child is included in shape if multiple children e.g., frm:crm:project:123:contract:456:approval:789 => project/contract
+// All children are included in shape e.g., frm:crm:project:123:contract:456:approval:789 => project/contract/approval
 func (id ID) Shape() string {
+	c := id.components()
 	buf := bytes.NewBuffer(nil)
-	buf.WriteString(id.Type().String())
-	if id.HasChild() {
+	buf.WriteString(c.Type.String())
+	for _, child := range c.Children {
 		buf.WriteString("/")
-		buf.WriteString(id.Child().Type().String())
+		buf.WriteString(child.Type.String())
 	}
 	if head, _, ok := id.Path(); ok {
 		buf.WriteString("#")
@@ -265,11 +257,16 @@ func (id ID) Shape() string {
 }
 
 // ShapeSlice returns the shape as a slice with 3 elements e.g. project/contract -> ["project", "contract", ""]
+// Nested children share the second element e.g. project/contract/approval -> ["project", "contract/approval", ""]
 func (id ID) ShapeSlice() []string {
+	c := id.components()
 	shape := [3]string{}
-	shape[0] = id.Type().String()
-	if id.HasChild() {
-		shape[1] = id.Child().Type().String()
+	shape[0] = c.Type.String()
+	for i, child := range c.Children {
+		if i > 0 {
+			shape[1] += "/"
+		}
+		shape[1] += child.Type.String()
 	}
 	if head, _, ok := id.Path(); ok {
 		shape[2] = head
@@ -281,13 +278,13 @@ func (id ID) String() string {
 	return string(id)
 }
 
+// Sub returns the child of the id with the type and value, escaping any reserved characters in the value
 func (id ID) Sub(st Type, idSub string) ID {
-	return ID(id.String() + sep + st.String() + sep + idSub)
+	return ID(id.String() + sep + st.String() + sep + EscapeValue(idSub))
 }
 
 func (id ID) Type() Type {
-	s, _ := id.partString(2)
-	return Type(s)
+	return id.components().Type
 }
 
 func (id ID) WithChild(child ID) ID {
@@ -295,8 +292,8 @@ func (id ID) WithChild(child ID) ID {
 }
 
 // WithPath returns the tertiary form of the id e.g. frm:crm:contact:123:address:456/a/b/c
-// currently only supports two levels of nesting e.g. a or a/b, but not a/b/c
-// WithPath replaces any existing path values
+// WithPath replaces any existing path values, skips blank tail values and escapes reserved characters; see also
+// WithPathSegments
 func (id ID) WithPath(head string, tail ...string) ID {
 	s := string(id)
 	if index := strings.Index(s, pathSep); index != -1 {
@@ -306,13 +303,13 @@ func (id ID) WithPath(head string, tail ...string) ID {
 	buf := bytes.NewBuffer(make([]byte, 0, 64))
 	buf.WriteString(s)
 	buf.WriteString(pathSep)
-	buf.WriteString(head)
+	buf.WriteString(EscapePath(head))
 	for _, t := range tail {
 		if t == "" {
 			continue
 		}
 		buf.WriteString(pathSep)
-		buf.WriteString(t)
+		buf.WriteString(EscapePath(t))
 	}
 	return ID(buf.String())
 }
@@ -325,14 +322,22 @@ func (id ID) WithPathStringer(head fmt.Stringer, tail ...fmt.Stringer) ID {
 	return id.WithPath(head.String(), ss...)
 }
 
+// IDMap is an index of ids for constant time membership checks; see IDSet.Map
 type IDMap map[ID]struct{}
 
+// Contains returns true if the id is part of the map
+func (vv IDMap) Contains(want ID) bool {
+	_, ok := vv[want]
+	return ok
+}
+
+// Slice returns the ids in lexical order
 func (vv IDMap) Slice() IDSet {
 	var idSet IDSet
 	for v := range vv {
 		idSet = append(idSet, v)
 	}
-	return idSet
+	return idSet.Sort()
 }
 
 type IDSet []ID
@@ -350,26 +355,36 @@ func (vv IDSet) Contains(want ID) bool {
 	return false
 }
 
+// MarshalDynamoDBAttributeValue marshals the non-blank ids as SS or, if there are none, NULL
+//
 //goland:noinspection GoMixedReceiverTypes
 func (vv IDSet) MarshalDynamoDBAttributeValue(item *dynamodb.AttributeValue) error {
-	if len(vv) == 0 {
-		item.NULL = aws.Bool(true)
-		return nil
+	if item == nil {
+		return fmt.Errorf("unable to marshal set: nil AttributeValue")
 	}
 
-	var ss []*string
-	for _, v := range vv {
-		if v == "" {
-			continue
-		}
-		ss = append(ss, aws.String(v.String()))
+	ss := vv.Trim().Strings()
+	if len(ss) == 0 {
+		item.NULL = aws.Bool(true)
+		return nil
 	}
 
-	item.SS = ss
+	item.SS = aws.StringSlice(ss)
 
 	return nil
 }
 
+// Strings exports ids as string slice
+//
+//goland:noinspection GoMixedReceiverTypes
+func (vv IDSet) Strings() []string {
+	var ss []string
+	for _, v := range vv {
+		ss = append(ss, v.String())
+	}
+	return ss
+}
+
 // Trim returns a new IDSet with the blank ids removed
 //
 //goland:noinspection GoMixedReceive
@@ -385,15 +400,30 @@ func (vv IDSet) Trim() IDSet {
 	return idSet
 }
 
+// UnmarshalDynamoDBAttributeValue unmarshals SS or, as an empty set, NULL
+//
 //goland:noinspection GoMixedReceiverTypes
 func (vv *IDSet) UnmarshalDynamoDBAttributeValue(item *dynamodb.AttributeValue) error {
-	if aws.BoolValue(item.NULL) || item.SS == nil {
+	if item == nil || aws.BoolValue(item.NULL) {
+		*vv = nil
 		return nil
 	}
 
 	var set IDSet
-	for _, s := range item.SS {
-		set = append(set, ID(aws.StringValue(s)))
+	switch {
+	case item.SS != nil:
+		for _, s := range item.SS {
+			set = append(set, ID(aws.StringValue(s)))
+		}
+	case item.L != nil: // e.g. written as a []string
+		for _, v := range item.L {
+			if v.S == nil {
+				return fmt.Errorf("unable to unmarshal set: want list of S attributes")
+			}
+			set = append(set, ID(aws.StringValue(v.S)))
+		}
+	default:
+		return fmt.Errorf("unable to unmarshal set: want SS, L or NULL attribute")
 	}
 
 	*vv = set
@@ -412,23 +442,14 @@ func (vv IDSet) Where(fn func(ID) bool) IDSet {
 	return results
 }
 
+//go:generate go run ./cmd/frngen -schema schema.yaml -out schema_gen.go
+
 type Service string
 
 func (s Service) String() string {
 	return string(s)
 }
 
-const (
-	ServiceAgentDB    Service = "adb"
-	ServiceCRM        Service = "crm"
-	ServiceFinance    Service = "fin"
-	ServiceOnboarding Service = "onboarding"
-	ServicePOC        Service = "poc"
-	ServiceRemie      Service = "remie"
-	ServiceRenie      Service = "renie"
-	ServiceSystem     Service = "system"
-)
-
 type Type string
 
 func (t Type) String() string {
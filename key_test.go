@@ -1,6 +1,7 @@
 package frn
 
 import (
+	"strings"
 	"testing"
 
 	"github.com/aws/aws-sdk-go/service/dynamodb/dynamodbattribute"
@@ -15,75 +16,6 @@ const (
 	TypeProject Type = "project"
 )
 
-func TestPartString(t *testing.T) {
-	var (
-		id    = "abc"
-		idSub = "def"
-		ns    = NewNamespace("", ServiceCRM)
-	)
-	testCases := map[string]struct {
-		Key    ID
-		Index  int
-		Want   string
-		WantOk bool
-	}{
-		"0": {
-			Key:    ns.New(TypeEntity, id),
-			Index:  0,
-			Want:   "fm",
-			WantOk: true,
-		},
-		"1": {
-			Key:    ns.New(TypeEntity, id),
-			Index:  1,
-			Want:   ServiceCRM.String(),
-			WantOk: true,
-		},
-		"2": {
-			Key:    ns.New(TypeEntity, id),
-			Index:  2,
-			Want:   TypeEntity.String(),
-			WantOk: true,
-		},
-		"3": {
-			Key:    ns.New(TypeEntity, id),
-			Index:  3,
-			Want:   id,
-			WantOk: true,
-		},
-		"4": {
-			Key:    ns.New(TypeEntity, id),
-			Index:  4,
-			WantOk: false,
-		},
-		"4 sub": {
-			Key:    ns.NewWithChild(TypeProject, id, TypeEvent, idSub),
-			Index:  4,
-			Want:   TypeEvent.String(),
-			WantOk: true,
-		},
-		"5 sub": {
-			Key:    ns.NewWithChild(TypeProject, id, TypeEvent, idSub),
-			Index:  5,
-			Want:   idSub,
-			WantOk: true,
-		},
-		"6 sub": {
-			Key:    ns.NewWithChild(TypeProject, id, TypeEvent, idSub),
-			Index:  6,
-			WantOk: false,
-		},
-	}
-
-	for label, tc := range testCases {
-		t.Run(label, func(t *testing.T) {
-			got, ok := tc.Key.partString(tc.Index)
-			assert.Equal(t, tc.WantOk, ok)
-			assert.Equal(t, tc.Want, got)
-		})
-	}
-}
-
 func TestID_Sub(t *testing.T) {
 	var (
 		id    = ksuid.New().String()
@@ -151,6 +83,20 @@ func TestParse(t *testing.T) {
 			TertiaryKey:   "key",
 			TertiaryValue: "",
 		},
+		"grandchild": {
+			ID:     "fm:crm:project:1:contract:2:approval:3",
+			Parent: "fm:crm:project:1:contract:2",
+			Child:  "fm:crm:approval:3",
+			Value:  "1",
+		},
+		"grandchild tertiary": {
+			ID:            "fm:crm:project:1:contract:2:approval:3/key/value",
+			Parent:        "fm:crm:project:1:contract:2",
+			Child:         "fm:crm:approval:3",
+			Value:         "1",
+			TertiaryKey:   "key",
+			TertiaryValue: "value",
+		},
 	}
 
 	for label, tc := range testCases {
@@ -198,6 +144,9 @@ func TestID(t *testing.T) {
 	assert.Equal(t, ServiceCRM, child.Service())
 	assert.Equal(t, TypeEvent, child.Type())
 	assert.Equal(t, child.String()+sep, child.ChildPrefix())
+
+	grandchild := ID("fm:crm:project:1:contract:2:approval:3")
+	assert.Equal(t, "fm:crm:project:1:contract:2:", grandchild.ChildPrefix())
 }
 
 func TestID_IsValid(t *testing.T) {
@@ -269,6 +218,10 @@ func TestID_IsValid(t *testing.T) {
 		},
 		"child multi": {
 			ID:      "namespace:service:type:value:second-type:second-value:third-type:third-value",
+			IsValid: true,
+		},
+		"child multi no third-type": {
+			ID:      "namespace:service:type:value:second-type:second-value::third-value",
 			IsValid: false,
 		},
 	}
@@ -322,6 +275,66 @@ func TestID_Base(t *testing.T) {
 	}
 }
 
+func TestID_Lineage(t *testing.T) {
+	testCases := map[string]struct {
+		ID            ID
+		WantLineage   []ID
+		WantAncestors []ID
+		WantDepth     int
+	}{
+		"empty": {
+			ID: "",
+		},
+		"parent": {
+			ID:          "fm:crm:project:1/key/value",
+			WantLineage: []ID{"fm:crm:project:1"},
+		},
+		"child": {
+			ID:            "fm:crm:project:1:contract:2",
+			WantLineage:   []ID{"fm:crm:project:1", "fm:crm:project:1:contract:2"},
+			WantAncestors: []ID{"fm:crm:project:1"},
+			WantDepth:     1,
+		},
+		"grandchild with path": {
+			ID: "fm:crm:project:1:contract:2:approval:3/key/value",
+			WantLineage: []ID{
+				"fm:crm:project:1",
+				"fm:crm:project:1:contract:2",
+				"fm:crm:project:1:contract:2:approval:3",
+			},
+			WantAncestors: []ID{"fm:crm:project:1", "fm:crm:project:1:contract:2"},
+			WantDepth:     2,
+		},
+		"unescaped value": {
+			ID:            "fm:crm:project:a.b:contract:2",
+			WantLineage:   []ID{"fm:crm:project:a.b", "fm:crm:project:a.b:contract:2"},
+			WantAncestors: []ID{"fm:crm:project:a.b"},
+			WantDepth:     1,
+		},
+	}
+
+	for label, tc := range testCases {
+		t.Run(label, func(t *testing.T) {
+			assert.Equal(t, tc.WantLineage, tc.ID.Lineage())
+			assert.Equal(t, tc.WantAncestors, tc.ID.Ancestors())
+			assert.Equal(t, tc.WantDepth, tc.ID.Depth())
+		})
+	}
+}
+
+func TestID_UnescapedValue(t *testing.T) {
+	// ids stored before values were escaped keep their spelling
+	id := ID("fm:crm:project:a.b:contract:c.d")
+	assert.Equal(t, ID("fm:crm:project:a.b"), id.Parent())
+	assert.Equal(t, "fm:crm:project:a.b:", id.ChildPrefix())
+	assert.True(t, strings.HasPrefix(id.String(), id.ChildPrefix()))
+	assert.Equal(t, ID("fm:crm:contract:c.d"), id.Child())
+
+	pk, sk := id.TableKey()
+	assert.Equal(t, ID("fm:crm:project:a.b"), pk)
+	assert.Equal(t, id, sk)
+}
+
 func TestID_IsParentType(t *testing.T) {
 	testCases := map[string]struct {
 		ID   ID
@@ -520,7 +533,11 @@ func TestID_Shape(t *testing.T) {
 		},
 		"parent multiple child": {
 			ID:   "fm:crm:project:1:contract:2:approval:4",
-			Want: "project/contract", // Note: only first child is considered
+			Want: "project/contract/approval",
+		},
+		"parent multiple child tertiary": {
+			ID:   "fm:crm:project:1:contract:2:approval:4/key/value",
+			Want: "project/contract/approval#key",
 		},
 	}
 
@@ -0,0 +1,204 @@
+package frn
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+)
+
+// defaultLoaderWait is how long a Loader collects ids before dispatching them
+const defaultLoaderWait = time.Millisecond
+
+type loaderCtxKey struct{}
+
+// LoaderOption configures a Loader
+type LoaderOption func(*Loader)
+
+// WithWait sets how long the Loader collects ids before dispatching a batch; defaults to 1ms
+func WithWait(d time.Duration) LoaderOption {
+	return func(l *Loader) {
+		l.wait = d
+	}
+}
+
+// WithMaxBatch limits the number of ids passed to a single batch e.g. 100 for DynamoDB BatchGetItem
+func WithMaxBatch(n int) LoaderOption {
+	return func(l *Loader) {
+		l.maxBatch = n
+	}
+}
+
+// WithGroupByShape batches ids by service and Shape, rather than by service and innermost type
+func WithGroupByShape() LoaderOption {
+	return func(l *Loader) {
+		l.groupKey = func(c Components, id ID) string {
+			return c.Service.String() + sep + id.Shape()
+		}
+	}
+}
+
+// loaderEntry holds the result of an id once done is closed
+type loaderEntry struct {
+	done   chan struct{}
+	result Result
+}
+
+// Loader batches and caches the loading of ids via a Resolver. Loads made within the wait window are grouped and
+// each group is dispatched to the Resolver once; results, including errors other than context cancellation or
+// deadlines, are cached for the life of the Loader.
+// A Loader is safe for concurrent use and is intended to be scoped to a single request; see NewLoaderContext.
+type Loader struct {
+	resolver *Resolver
+	wait     time.Duration
+	maxBatch int
+	groupKey func(c Components, id ID) string
+
+	mu      sync.Mutex
+	cache   map[ID]*loaderEntry
+	pending IDSet
+	timer   *time.Timer
+}
+
+func NewLoader(r *Resolver, opts ...LoaderOption) *Loader {
+	l := &Loader{
+		resolver: r,
+		wait:     defaultLoaderWait,
+		groupKey: func(c Components, _ ID) string {
+			return c.Service.String() + sep + c.leaf().Type.String()
+		},
+		cache: map[ID]*loaderEntry{},
+	}
+	for _, opt := range opts {
+		opt(l)
+	}
+	return l
+}
+
+// NewLoaderContext returns a copy of ctx holding a new Loader for the Resolver; see LoaderFromContext
+func NewLoaderContext(ctx context.Context, r *Resolver, opts ...LoaderOption) context.Context {
+	return context.WithValue(ctx, loaderCtxKey{}, NewLoader(r, opts...))
+}
+
+// LoaderFromContext returns the Loader held by ctx, if any
+func LoaderFromContext(ctx context.Context) (*Loader, bool) {
+	l, ok := ctx.Value(loaderCtxKey{}).(*Loader)
+	return l, ok
+}
+
+// Load returns the entity identified by id, waiting for the batch containing it to be loaded
+func (l *Loader) Load(ctx context.Context, id ID) (any, error) {
+	results := l.LoadMany(ctx, IDSet{id})
+	return results[0].Value, results[0].Err
+}
+
+// LoadMany returns a Result for each id in the same order, waiting for the batches containing them to be loaded.
+// Batches are dispatched with the values, but not the cancellation or deadline, of the context of the load that
+// started the wait window, so that one caller giving up does not fail the batch for the others.
+func (l *Loader) LoadMany(ctx context.Context, ids IDSet) []Result {
+	entries := make([]*loaderEntry, len(ids))
+
+	l.mu.Lock()
+	for i, id := range ids {
+		e, ok := l.cache[id]
+		if !ok {
+			e = &loaderEntry{done: make(chan struct{})}
+			l.cache[id] = e
+			l.pending = append(l.pending, id)
+		}
+		entries[i] = e
+	}
+	switch {
+	case len(l.pending) == 0:
+	case l.maxBatch > 0 && len(l.pending) >= l.maxBatch:
+		go l.load(detachedContext{ctx}, l.takePending())
+	case l.timer == nil:
+		l.timer = time.AfterFunc(l.wait, func() {
+			l.mu.Lock()
+			batch := l.takePending()
+			l.mu.Unlock()
+			l.load(detachedContext{ctx}, batch)
+		})
+	}
+	l.mu.Unlock()
+
+	results := make([]Result, len(ids))
+	for i, e := range entries {
+		select {
+		case <-e.done:
+			results[i] = e.result
+		case <-ctx.Done():
+			results[i] = Result{ID: ids[i], Err: ctx.Err()}
+		}
+	}
+	return results
+}
+
+// takePending returns and clears the pending ids; l.mu must be held
+func (l *Loader) takePending() IDSet {
+	if l.timer != nil {
+		l.timer.Stop()
+		l.timer = nil
+	}
+	batch := l.pending
+	l.pending = nil
+	return batch
+}
+
+// load groups the ids, resolves each group concurrently and completes their entries
+func (l *Loader) load(ctx context.Context, ids IDSet) {
+	var (
+		groups = map[string]IDSet{}
+		order  []string
+		wg     sync.WaitGroup
+	)
+	for _, id := range ids {
+		key := l.groupKey(id.components(), id)
+		if _, ok := groups[key]; !ok {
+			order = append(order, key)
+		}
+		groups[key] = append(groups[key], id)
+	}
+
+	for _, key := range order {
+		group := groups[key]
+		for len(group) > 0 {
+			n := len(group)
+			if l.maxBatch > 0 && n > l.maxBatch {
+				n = l.maxBatch
+			}
+
+			wg.Add(1)
+			go func(batch IDSet) {
+				defer wg.Done()
+				l.complete(l.resolver.ResolveMany(ctx, batch))
+			}(group[:n])
+			group = group[n:]
+		}
+	}
+
+	wg.Wait()
+}
+
+func (l *Loader) complete(results []Result) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	for _, result := range results {
+		e := l.cache[result.ID]
+		e.result = result
+		close(e.done)
+		if errors.Is(result.Err, context.Canceled) || errors.Is(result.Err, context.DeadlineExceeded) {
+			delete(l.cache, result.ID) // retry on the next load rather than failing for the life of the Loader
+		}
+	}
+}
+
+// detachedContext holds the values of a context, but is never cancelled and has no deadline
+type detachedContext struct {
+	context.Context
+}
+
+func (detachedContext) Deadline() (time.Time, bool) { return time.Time{}, false }
+func (detachedContext) Done() <-chan struct{}       { return nil }
+func (detachedContext) Err() error                  { return nil }
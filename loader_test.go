@@ -0,0 +1,174 @@
+package frn
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/tj/assert"
+)
+
+// recordingResolver returns a Resolver for projects and contracts that records the size of each batch
+func recordingResolver() (*Resolver, func() []int) {
+	var (
+		mu    sync.Mutex
+		sizes []int
+	)
+	batch := func(ctx context.Context, ids IDSet) (map[ID]any, error) {
+		mu.Lock()
+		sizes = append(sizes, len(ids))
+		mu.Unlock()
+
+		values := map[ID]any{}
+		for _, id := range ids {
+			values[id] = id.String()
+		}
+		return values, nil
+	}
+
+	r := NewResolver().
+		RegisterBatch(ServiceCRM, TypeProject, batch).
+		RegisterBatch(ServiceCRM, TypeContract, batch)
+
+	return r, func() []int {
+		mu.Lock()
+		defer mu.Unlock()
+		sort.Ints(sizes)
+		return sizes
+	}
+}
+
+func TestLoader(t *testing.T) {
+	testCases := map[string]struct {
+		Options   []LoaderOption
+		IDs       IDSet
+		WantSizes []int
+	}{
+		"by type": {
+			IDs: IDSet{
+				"fm:crm:project:1",
+				"fm:crm:project:2",
+				"fm:crm:project:1:contract:1",
+				"fm:crm:project:1:contract:1/account",
+				"fm:crm:project:2",
+			},
+			WantSizes: []int{2, 2},
+		},
+		"by shape": {
+			Options: []LoaderOption{WithGroupByShape()},
+			IDs: IDSet{
+				"fm:crm:project:1",
+				"fm:crm:project:2",
+				"fm:crm:project:1:contract:1",
+				"fm:crm:project:1:contract:1/account",
+			},
+			WantSizes: []int{1, 1, 2},
+		},
+		"max batch": {
+			Options: []LoaderOption{WithMaxBatch(2), WithWait(time.Hour)},
+			IDs: IDSet{
+				"fm:crm:project:1",
+				"fm:crm:project:2",
+				"fm:crm:project:3",
+				"fm:crm:project:4",
+			},
+			WantSizes: []int{2, 2},
+		},
+	}
+
+	for label, tc := range testCases {
+		t.Run(label, func(t *testing.T) {
+			var (
+				r, sizes = recordingResolver()
+				ctx      = NewLoaderContext(context.Background(), r, append([]LoaderOption{WithWait(10 * time.Millisecond)}, tc.Options...)...)
+				wg       sync.WaitGroup
+			)
+
+			loader, ok := LoaderFromContext(ctx)
+			assert.True(t, ok)
+
+			for _, id := range tc.IDs {
+				wg.Add(1)
+				go func(id ID) {
+					defer wg.Done()
+					v, err := loader.Load(ctx, id)
+					assert.Nil(t, err)
+					assert.Equal(t, id.String(), v)
+				}(id)
+			}
+			wg.Wait()
+			assert.Equal(t, tc.WantSizes, sizes())
+
+			// results are cached for the life of the loader
+			results := loader.LoadMany(ctx, tc.IDs)
+			for i, result := range results {
+				assert.Equal(t, tc.IDs[i], result.ID)
+				assert.Equal(t, tc.IDs[i].String(), result.Value)
+			}
+			assert.Equal(t, tc.WantSizes, sizes())
+		})
+	}
+}
+
+func TestLoader_Errors(t *testing.T) {
+	var (
+		loader = NewLoader(NewResolver())
+		ctx    = context.Background()
+	)
+
+	_, err := loader.Load(ctx, "fm:crm:project:1")
+	assert.True(t, errors.Is(err, ErrNoResolver))
+
+	canceled, cancel := context.WithCancel(ctx)
+	cancel()
+	_, err = NewLoader(NewResolver(), WithWait(time.Hour)).Load(canceled, "fm:crm:project:1")
+	assert.True(t, errors.Is(err, context.Canceled))
+}
+
+func TestLoader_Cancel(t *testing.T) {
+	var (
+		mu       sync.Mutex
+		calls    int
+		timeouts int // timeouts is the number of calls that fail as if the store timed out
+	)
+	r := NewResolver().RegisterBatch(ServiceCRM, TypeProject, func(ctx context.Context, ids IDSet) (map[ID]any, error) {
+		mu.Lock()
+		defer mu.Unlock()
+		calls++
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		if timeouts > 0 {
+			timeouts--
+			return nil, context.DeadlineExceeded
+		}
+		return map[ID]any{ids[0]: ids[0].String()}, nil
+	})
+	id := ID("fm:crm:project:1")
+
+	// the first caller gives up within the wait window, but the batch still completes for the second
+	loader := NewLoader(r, WithWait(20*time.Millisecond))
+	short, cancel := context.WithTimeout(context.Background(), 5*time.Millisecond)
+	defer cancel()
+	_, err := loader.Load(short, id)
+	assert.True(t, errors.Is(err, context.DeadlineExceeded))
+
+	v, err := loader.Load(context.Background(), id)
+	assert.Nil(t, err)
+	assert.Equal(t, id.String(), v)
+	assert.Equal(t, 1, calls)
+
+	// context errors returned by the resolver are not cached
+	calls, timeouts = 0, 1
+	loader = NewLoader(r)
+	_, err = loader.Load(context.Background(), id)
+	assert.True(t, errors.Is(err, context.DeadlineExceeded))
+
+	v, err = loader.Load(context.Background(), id)
+	assert.Nil(t, err)
+	assert.Equal(t, id.String(), v)
+	assert.Equal(t, 2, calls)
+}
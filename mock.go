@@ -1,8 +1,12 @@
 package frn
 
 import (
+	"io"
+	"math/rand"
 	"strconv"
+	"sync"
 	"sync/atomic"
+	"time"
 )
 
 type Sequence struct {
@@ -19,3 +23,31 @@ func NewSequence(initialValue int64) *Sequence {
 		value: initialValue,
 	}
 }
+
+// NewClock returns a clock, for use with WithClock, that returns start and then advances by step on each call.
+// KSUIDs have a resolution of one second, so a step of at least time.Second keeps KSUID values time ordered.
+func NewClock(start time.Time, step time.Duration) func() time.Time {
+	var calls int64
+	return func() time.Time {
+		n := atomic.AddInt64(&calls, 1) - 1
+		return start.Add(time.Duration(n) * step)
+	}
+}
+
+// lockedRand is a rand.Rand that is safe for concurrent use
+type lockedRand struct {
+	mu   sync.Mutex
+	rand *rand.Rand
+}
+
+func (r *lockedRand) Read(p []byte) (int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.rand.Read(p)
+}
+
+// NewRand returns a reproducible source of entropy, for use with WithRand; the same seed returns the same bytes.
+// It is NOT cryptographically secure and is intended for tests.
+func NewRand(seed int64) io.Reader {
+	return &lockedRand{rand: rand.New(rand.NewSource(seed))}
+}
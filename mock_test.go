@@ -41,3 +41,10 @@ func TestNewSequence(t *testing.T) {
 	contractID := handler.DoWork(projectID)
 	assert.EqualValues(t, "dev:crm:project:1:contract:2", contractID)
 }
+
+func TestNewRand(t *testing.T) {
+	a, b := make([]byte, 16), make([]byte, 16)
+	_, _ = frn.NewRand(42).Read(a)
+	_, _ = frn.NewRand(42).Read(b)
+	assert.Equal(t, a, b)
+}
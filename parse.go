@@ -0,0 +1,215 @@
+package frn
+
+import (
+	"fmt"
+	"strings"
+)
+
+// ParseCode classifies why an id could not be parsed
+type ParseCode string
+
+const (
+	ParseEmpty     ParseCode = "empty"     // ParseEmpty is used when the id is blank
+	ParseMissing   ParseCode = "missing"   // ParseMissing is used when a part of the id is blank or absent
+	ParseCharacter ParseCode = "character" // ParseCharacter is used when a part of the id holds a forbidden character
+	ParseEscape    ParseCode = "escape"    // ParseEscape is used when a value or path holds a malformed or non-canonical escape
+)
+
+// ParseError describes why an id could not be parsed and where the problem was found
+type ParseError struct {
+	ID     string    // ID that failed to parse
+	Offset int       // Offset of the offending byte within ID
+	Code   ParseCode // Code classifies the reason e.g. ParseMissing
+	Part   string    // Part of the id at fault e.g. type, child value or path; blank for ParseEmpty
+	Char   byte      // Char is the offending character for ParseCharacter
+	Reason string    // Reason the id was rejected e.g. missing type
+}
+
+func (e *ParseError) Error() string {
+	return fmt.Sprintf("invalid frn, %q: %v at offset %v", e.ID, e.Reason, e.Offset)
+}
+
+// Segment holds a type and value pair e.g. contract:456
+type Segment struct {
+	Type  Type
+	Value string
+}
+
+// Components holds the decomposed parts of an id e.g. fm:crm:project:1:contract:2/key/value
+// Values and path segments are held unescaped; see EscapeValue
+type Components struct {
+	Env      string    // Env e.g. fm
+	Service  Service   // Service e.g. crm
+	Type     Type      // Type of the parent e.g. project
+	Value    string    // Value of the parent e.g. 1
+	Children []Segment // Children in order of nesting e.g. contract:2
+	Path     []string  // Path segments e.g. key, value
+}
+
+// ID reassembles the components into an id
+func (c Components) ID() ID {
+	buf := strings.Builder{}
+	buf.WriteString(c.Env)
+	buf.WriteString(sep)
+	buf.WriteString(c.Service.String())
+	buf.WriteString(sep)
+	buf.WriteString(c.Type.String())
+	buf.WriteString(sep)
+	buf.WriteString(EscapeValue(c.Value))
+	for _, child := range c.Children {
+		buf.WriteString(sep)
+		buf.WriteString(child.Type.String())
+		buf.WriteString(sep)
+		buf.WriteString(EscapeValue(child.Value))
+	}
+	for _, segment := range c.Path {
+		buf.WriteString(pathSep)
+		buf.WriteString(EscapePath(segment))
+	}
+	return ID(buf.String())
+}
+
+// Namespace returns the namespace of the components e.g. fm:crm
+func (c Components) Namespace() Namespace {
+	return Namespace(c.Env + sep + c.Service.String())
+}
+
+// leaf returns the innermost segment e.g. fm:crm:project:1:contract:2 => contract:2
+func (c Components) leaf() Segment {
+	if n := len(c.Children); n > 0 {
+		return c.Children[n-1]
+	}
+	return Segment{Type: c.Type, Value: c.Value}
+}
+
+// Parse decomposes s into its components, returning a *ParseError if s is not a valid id.
+// see test case, TestID_IsValid, for examples e.g. fm:crm:contact:1234
+func Parse(s string) (Components, error) {
+	c, err := parse(s)
+	if err != nil {
+		return Components{}, err
+	}
+	return c, nil
+}
+
+// parse decomposes s on a best effort basis; components are populated even when an error is returned
+func parse(s string) (Components, error) {
+	var (
+		c   Components
+		err *ParseError
+	)
+	fail := func(offset int, code ParseCode, part string, char byte) {
+		if err != nil {
+			return
+		}
+		err = &ParseError{ID: s, Offset: offset, Code: code, Part: part, Char: char}
+		switch code {
+		case ParseMissing:
+			err.Reason = "missing " + part
+		case ParseCharacter:
+			err.Reason = fmt.Sprintf("invalid character %q in %v", char, part)
+		case ParseEscape:
+			err.Reason = "invalid escape in " + part
+		}
+	}
+
+	if s == "" {
+		return c, &ParseError{ID: s, Code: ParseEmpty, Reason: "empty id"}
+	}
+
+	base, path, hasPath := s, "", false
+	if index := strings.Index(s, pathSep); index != -1 {
+		base, path, hasPath = s[:index], s[index+1:], true
+	}
+
+	offset := 0
+	for i, part := range strings.Split(base, sep) {
+		name := partName(i)
+		if part == "" && name != "child value" {
+			fail(offset, ParseMissing, name, 0)
+		}
+		isValue := name == "value" || name == "child value"
+		if index := strings.IndexFunc(part, func(r rune) bool { return !isValueChar(r) && !(isValue && r == escapeChar) }); index != -1 {
+			fail(offset+index, ParseCharacter, name, part[index])
+		}
+		if index := invalidEscape(part, isValueChar); isValue && index != -1 {
+			fail(offset+index, ParseEscape, name, 0)
+		}
+
+		switch i {
+		case 0:
+			c.Env = part
+		case 1:
+			c.Service = Service(part)
+		case 2:
+			c.Type = Type(part)
+		case 3:
+			c.Value = Unescape(part)
+		default:
+			if i%2 == 0 {
+				c.Children = append(c.Children, Segment{Type: Type(part)})
+			} else {
+				c.Children[len(c.Children)-1].Value = Unescape(part)
+			}
+		}
+		offset += len(part) + len(sep)
+	}
+
+	switch n := strings.Count(base, sep) + 1; {
+	case n < 4:
+		fail(len(base), ParseMissing, partName(n), 0)
+	case n%2 == 1:
+		fail(len(base), ParseMissing, "child value", 0)
+	}
+
+	if hasPath {
+		if path == "" {
+			fail(len(s), ParseMissing, "path", 0)
+		}
+		if index := strings.IndexFunc(path, func(r rune) bool { return !isPathChar(r) && r != escapeChar && string(r) != pathSep }); index != -1 {
+			fail(len(base)+1+index, ParseCharacter, "path", path[index])
+		}
+		if index := invalidEscape(path, isPathChar); index != -1 {
+			fail(len(base)+1+index, ParseEscape, "path", 0)
+		}
+		if path != "" {
+			for _, segment := range strings.Split(path, pathSep) {
+				c.Path = append(c.Path, Unescape(segment))
+			}
+		}
+	}
+
+	if err != nil {
+		return c, err
+	}
+	return c, nil
+}
+
+// partName returns the name of the i-th colon separated part of an id
+func partName(i int) string {
+	switch i {
+	case 0:
+		return "env"
+	case 1:
+		return "service"
+	case 2:
+		return "type"
+	case 3:
+		return "value"
+	default:
+		if i%2 == 0 {
+			return "child type"
+		}
+		return "child value"
+	}
+}
+
+// isValueChar returns true if r may appear in the env, service, type or value of an id
+func isValueChar(r rune) bool {
+	return r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' || r == '-' || r == '_'
+}
+
+// isPathChar returns true if r may appear in a path segment
+func isPathChar(r rune) bool {
+	return r >= 'a' && r <= 'z' || r >= '0' && r <= '9' || r == '-' || r == '_'
+}
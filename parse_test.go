@@ -0,0 +1,171 @@
+package frn
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/tj/assert"
+)
+
+func TestParse_Components(t *testing.T) {
+	testCases := map[string]struct {
+		ID   string
+		Want Components
+	}{
+		"parent": {
+			ID: "fm:crm:entity:abc",
+			Want: Components{
+				Env:     "fm",
+				Service: ServiceCRM,
+				Type:    TypeEntity,
+				Value:   "abc",
+			},
+		},
+		"child": {
+			ID: "fm:crm:project:abc:event:def",
+			Want: Components{
+				Env:      "fm",
+				Service:  ServiceCRM,
+				Type:     TypeProject,
+				Value:    "abc",
+				Children: []Segment{{Type: TypeEvent, Value: "def"}},
+			},
+		},
+		"child - no value": {
+			ID: "fm:crm:project:abc:event:",
+			Want: Components{
+				Env:      "fm",
+				Service:  ServiceCRM,
+				Type:     TypeProject,
+				Value:    "abc",
+				Children: []Segment{{Type: TypeEvent}},
+			},
+		},
+		"child with path": {
+			ID: "fm:crm:project:abc:event:def/key/value/more",
+			Want: Components{
+				Env:      "fm",
+				Service:  ServiceCRM,
+				Type:     TypeProject,
+				Value:    "abc",
+				Children: []Segment{{Type: TypeEvent, Value: "def"}},
+				Path:     []string{"key", "value", "more"},
+			},
+		},
+		"grandchild": {
+			ID: "fm:crm:project:abc:event:def:entity:ghi",
+			Want: Components{
+				Env:     "fm",
+				Service: ServiceCRM,
+				Type:    TypeProject,
+				Value:   "abc",
+				Children: []Segment{
+					{Type: TypeEvent, Value: "def"},
+					{Type: TypeEntity, Value: "ghi"},
+				},
+			},
+		},
+	}
+
+	for label, tc := range testCases {
+		t.Run(label, func(t *testing.T) {
+			got, err := Parse(tc.ID)
+			assert.Nil(t, err)
+			assert.Equal(t, tc.Want, got)
+			assert.Equal(t, ID(tc.ID), got.ID())
+		})
+	}
+}
+
+func TestParse_Errors(t *testing.T) {
+	testCases := map[string]struct {
+		ID         string
+		WantOffset int
+		WantCode   ParseCode
+		WantPart   string
+		WantReason string
+	}{
+		"empty": {
+			ID:         "",
+			WantOffset: 0,
+			WantCode:   ParseEmpty,
+			WantReason: "empty id",
+		},
+		"no env": {
+			ID:         ":crm:project:1",
+			WantOffset: 0,
+			WantCode:   ParseMissing,
+			WantPart:   "env",
+			WantReason: "missing env",
+		},
+		"no type": {
+			ID:         "fm:crm::1",
+			WantOffset: 7,
+			WantCode:   ParseMissing,
+			WantPart:   "type",
+			WantReason: "missing type",
+		},
+		"too short": {
+			ID:         "fm:crm:project",
+			WantOffset: 14,
+			WantCode:   ParseMissing,
+			WantPart:   "value",
+			WantReason: "missing value",
+		},
+		"bad value": {
+			ID:         "fm:crm:project:a.b",
+			WantOffset: 16,
+			WantCode:   ParseCharacter,
+			WantPart:   "value",
+			WantReason: `invalid character '.' in value`,
+		},
+		"no child type": {
+			ID:         "fm:crm:project:1::2",
+			WantOffset: 17,
+			WantCode:   ParseMissing,
+			WantPart:   "child type",
+			WantReason: "missing child type",
+		},
+		"no child value": {
+			ID:         "fm:crm:project:1:contract",
+			WantOffset: 25,
+			WantCode:   ParseMissing,
+			WantPart:   "child value",
+			WantReason: "missing child value",
+		},
+		"no grandchild value": {
+			ID:         "fm:crm:project:1:contract:2:approval",
+			WantOffset: 36,
+			WantCode:   ParseMissing,
+			WantPart:   "child value",
+			WantReason: "missing child value",
+		},
+		"empty path": {
+			ID:         "fm:crm:project:1/",
+			WantOffset: 17,
+			WantCode:   ParseMissing,
+			WantPart:   "path",
+			WantReason: "missing path",
+		},
+		"upper case path": {
+			ID:         "fm:crm:project:1/key/Value",
+			WantOffset: 21,
+			WantCode:   ParseCharacter,
+			WantPart:   "path",
+			WantReason: `invalid character 'V' in path`,
+		},
+	}
+
+	for label, tc := range testCases {
+		t.Run(label, func(t *testing.T) {
+			_, err := Parse(tc.ID)
+
+			var pe *ParseError
+			assert.True(t, errors.As(err, &pe))
+			assert.Equal(t, tc.WantOffset, pe.Offset)
+			assert.Equal(t, tc.WantCode, pe.Code)
+			assert.Equal(t, tc.WantPart, pe.Part)
+			assert.Equal(t, tc.WantReason, pe.Reason)
+		})
+	}
+}
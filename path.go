@@ -0,0 +1,81 @@
+package frn
+
+import (
+	"strconv"
+	"strings"
+	"time"
+)
+
+// PathSegments returns the segments of the path e.g. fm:fin:entity:1/account/ar/2024/q3 => [account ar 2024 q3]
+func (id ID) PathSegments() []string {
+	return id.components().Path
+}
+
+// WithPathSegments returns the id with its path replaced by the escaped segments, skipping blank segments; without
+// segments, the id is returned sans path e.g. WithPathSegments("account", "ar", "2024") => .../account/ar/2024
+func (id ID) WithPathSegments(segments ...string) ID {
+	var path []string
+	for _, segment := range segments {
+		if segment != "" {
+			path = append(path, EscapePath(segment))
+		}
+	}
+
+	if len(path) == 0 {
+		return id.Base()
+	}
+	return ID(id.Base().String() + pathSep + strings.Join(path, pathSep))
+}
+
+// AppendPath returns the id with the segments appended to its path, skipping blank segments
+// e.g. fm:fin:entity:1/account/ar, 2024 => fm:fin:entity:1/account/ar/2024
+func (id ID) AppendPath(segments ...string) ID {
+	return id.WithPathSegments(append(id.PathSegments(), segments...)...)
+}
+
+// ReplacePathSegment returns the id with the i-th segment of its path replaced; ok is false, and the id returned
+// unchanged, if it has no i-th segment or segment is blank or the same e.g. fm:fin:entity:1/account/ar/2024, 2, 2025
+// => fm:fin:entity:1/account/ar/2025
+func (id ID) ReplacePathSegment(i int, segment string) (ID, bool) {
+	path := id.PathSegments()
+	if i < 0 || i >= len(path) || segment == "" || path[i] == segment {
+		return id, false
+	}
+	path[i] = segment
+	return id.WithPathSegments(path...), true
+}
+
+// PathSegment returns the i-th segment of the path
+func (id ID) PathSegment(i int) (string, bool) {
+	path := id.PathSegments()
+	if i < 0 || i >= len(path) {
+		return "", false
+	}
+	return path[i], true
+}
+
+// PathInt returns the i-th segment of the path as an integer e.g. fm:fin:entity:1/account/ar/2024, 2 => 2024
+func (id ID) PathInt(i int) (int64, bool) {
+	segment, ok := id.PathSegment(i)
+	if !ok {
+		return 0, false
+	}
+	v, err := strconv.ParseInt(segment, 10, 64)
+	if err != nil {
+		return 0, false
+	}
+	return v, true
+}
+
+// PathTime returns the i-th segment of the path parsed with the layout e.g. .../account/ar/2024-06, 2, 2006-01
+func (id ID) PathTime(i int, layout string) (time.Time, bool) {
+	segment, ok := id.PathSegment(i)
+	if !ok {
+		return time.Time{}, false
+	}
+	t, err := time.Parse(layout, segment)
+	if err != nil {
+		return time.Time{}, false
+	}
+	return t, true
+}
@@ -0,0 +1,101 @@
+package frn
+
+import (
+	"testing"
+	"time"
+
+	"github.com/tj/assert"
+)
+
+func TestID_PathSegments(t *testing.T) {
+	testCases := map[string]struct {
+		Got  ID
+		Want ID
+	}{
+		"with segments": {
+			Got:  ID("fm:fin:entity:1/old").WithPathSegments("account", "", "ar", "2024", "q3"),
+			Want: "fm:fin:entity:1/account/ar/2024/q3",
+		},
+		"with no segments": {
+			Got:  ID("fm:fin:entity:1/account").WithPathSegments(),
+			Want: "fm:fin:entity:1",
+		},
+		"append": {
+			Got:  ID("fm:fin:entity:1/account/ar").AppendPath("2024"),
+			Want: "fm:fin:entity:1/account/ar/2024",
+		},
+		"append - no path": {
+			Got:  ID("fm:fin:entity:1").AppendPath("account"),
+			Want: "fm:fin:entity:1/account",
+		},
+	}
+
+	for label, tc := range testCases {
+		t.Run(label, func(t *testing.T) {
+			assert.Equal(t, tc.Want, tc.Got)
+		})
+	}
+
+	id := ID("fm:fin:entity:1:ledger:2/account/ar/2024/q3")
+	assert.Equal(t, []string{"account", "ar", "2024", "q3"}, id.PathSegments())
+	assert.Nil(t, ID("fm:fin:entity:1").PathSegments())
+}
+
+func TestID_ReplacePathSegment(t *testing.T) {
+	testCases := map[string]struct {
+		I       int
+		Segment string
+		Want    ID
+		WantOk  bool
+	}{
+		"replace": {
+			I:       2,
+			Segment: "2025",
+			Want:    "fm:fin:entity:1/account/ar/2025",
+			WantOk:  true,
+		},
+		"out of range": {
+			I:       3,
+			Segment: "q3",
+			Want:    "fm:fin:entity:1/account/ar/2024",
+		},
+		"blank": {
+			I:    1,
+			Want: "fm:fin:entity:1/account/ar/2024",
+		},
+		"same": {
+			I:       1,
+			Segment: "ar",
+			Want:    "fm:fin:entity:1/account/ar/2024",
+		},
+	}
+
+	for label, tc := range testCases {
+		t.Run(label, func(t *testing.T) {
+			got, ok := ID("fm:fin:entity:1/account/ar/2024").ReplacePathSegment(tc.I, tc.Segment)
+			assert.Equal(t, tc.WantOk, ok)
+			assert.Equal(t, tc.Want, got)
+		})
+	}
+}
+
+func TestID_PathTyped(t *testing.T) {
+	id := ID("fm:fin:entity:1/account/ar/2024/2024-06")
+
+	v, ok := id.PathInt(2)
+	assert.True(t, ok)
+	assert.Equal(t, int64(2024), v)
+
+	_, ok = id.PathInt(1)
+	assert.False(t, ok)
+
+	_, ok = id.PathInt(9)
+	assert.False(t, ok)
+
+	at, ok := id.PathTime(3, "2006-01")
+	assert.True(t, ok)
+	assert.Equal(t, time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC), at)
+
+	_, ok = id.PathTime(1, "2006-01")
+	assert.False(t, ok)
+}
@@ -0,0 +1,341 @@
+package frn
+
+import (
+	"fmt"
+	"strings"
+)
+
+const (
+	wildcard    = "*"  // wildcard matches any single env, service, type or path head
+	anyShape    = "**" // anyShape matches any shape
+	alternation = "|"  // alternation separates the alternatives of a pattern
+	negation    = "!"  // negation excludes ids matching the alternative
+)
+
+// Pattern is a compiled shape pattern e.g. project/contract#account; see README for the grammar.
+// Pattern is the single grammar for shapes shared by validation and the shape helpers.
+type Pattern struct {
+	raw   string
+	terms []term
+}
+
+// term is a single alternative of a pattern e.g. !dev:crm:project/contract#account
+type term struct {
+	negate   bool
+	env      string   // env; blank or wildcard matches any env
+	service  string   // service; blank or wildcard matches any service
+	any      bool     // any matches any valid id
+	parent   string   // parent type; blank or wildcard matches any parent
+	compound bool     // compound requires at least one child
+	children []string // children types in order of nesting; blank matches any children, wildcard any one child
+	hasPath  bool     // hasPath requires a path
+	path     []string // path segments the path must begin with e.g. account, ar; wildcard matches any one segment
+}
+
+// CompilePattern parses s into a Pattern; an empty pattern matches any valid id
+func CompilePattern(s string) (Pattern, error) {
+	p := Pattern{raw: s}
+	for _, alt := range strings.Split(s, alternation) {
+		t, err := compileTerm(s, alt)
+		if err != nil {
+			return Pattern{}, err
+		}
+		p.terms = append(p.terms, t)
+	}
+	return p, nil
+}
+
+// MustCompilePattern is like CompilePattern, but panics if s cannot be compiled
+func MustCompilePattern(s string) Pattern {
+	p, err := CompilePattern(s)
+	if err != nil {
+		panic(err)
+	}
+	return p
+}
+
+// compileTerm parses a single alternative, alt, of the pattern, s
+func compileTerm(s, alt string) (term, error) {
+	var t term
+	if strings.HasPrefix(alt, negation) {
+		t.negate, alt = true, alt[len(negation):]
+	}
+	if alt == "" && s != "" {
+		return term{}, fmt.Errorf("invalid frn pattern, %q: empty alternative", s)
+	}
+
+	parts := strings.Split(alt, sep)
+	switch len(parts) {
+	case 1:
+	case 2:
+		t.service = parts[0]
+	case 3:
+		t.env, t.service = parts[0], parts[1]
+	default:
+		return term{}, fmt.Errorf("invalid frn pattern, %q: too many qualifiers", s)
+	}
+	if err := checkPatternPart(s, "env", t.env); err != nil {
+		return term{}, err
+	}
+	if err := checkPatternPart(s, "service", t.service); err != nil {
+		return term{}, err
+	}
+
+	shape := parts[len(parts)-1]
+	if shape == anyShape || alt == "" {
+		t.any = true
+		return t, nil
+	}
+
+	if index := strings.Index(shape, "#"); index != -1 {
+		var path string
+		shape, path, t.hasPath = shape[:index], shape[index+1:], true
+		if path != "" {
+			t.path = strings.Split(path, pathSep)
+		}
+		for _, segment := range t.path {
+			if err := checkPatternPart(s, "path", segment); err != nil {
+				return term{}, err
+			}
+		}
+	}
+
+	types := strings.Split(shape, "/")
+	t.parent = types[0]
+	if err := checkPatternPart(s, "parent", t.parent); err != nil {
+		return term{}, err
+	}
+
+	if len(types) > 1 {
+		t.compound = true
+		if len(types) > 2 || types[1] != "" {
+			t.children = types[1:]
+		}
+		for _, child := range t.children {
+			if child == "" {
+				return term{}, fmt.Errorf("invalid frn pattern, %q: missing child", s)
+			}
+			if err := checkPatternPart(s, "child", child); err != nil {
+				return term{}, err
+			}
+		}
+	}
+
+	return t, nil
+}
+
+// patternFromShape builds a Pattern from a shape slice; see ShapeSlice
+func patternFromShape(shape []string) Pattern {
+	var path []string
+	if shape[2] != "" {
+		path = []string{shape[2]}
+	}
+	return newShapePattern(shape[0], shape[1], path)
+}
+
+// newShapePattern builds a Pattern from a parent type, the slash separated children types and the path segments
+func newShapePattern(parent, children string, path []string) Pattern {
+	t := term{
+		parent:   parent,
+		compound: children != "",
+		hasPath:  len(path) > 0,
+		path:     path,
+	}
+	if t.compound {
+		t.children = strings.Split(children, "/")
+	}
+	return Pattern{raw: t.format(), terms: []term{t}}
+}
+
+func checkPatternPart(s, name, part string) error {
+	if part == wildcard {
+		return nil
+	}
+	if index := strings.IndexFunc(part, func(r rune) bool { return !isValueChar(r) }); index != -1 {
+		return fmt.Errorf("invalid frn pattern, %q: invalid character %q in %v", s, part[index], name)
+	}
+	return nil
+}
+
+// Explain returns a *MismatchError describing why the id does not match the pattern or nil if it does; a *ParseError
+// is returned instead if the id is not valid.
+// An id matches if it is valid, matches any alternative, or there are only negated alternatives, and matches no negated
+// alternative.
+func (p Pattern) Explain(id ID) error {
+	if _, err := Parse(id.String()); err != nil {
+		return err
+	}
+
+	for _, t := range p.terms {
+		if t.negate && t.explain(id) == nil {
+			return &MismatchError{ID: id, Part: PartPattern, Want: t.format(), Got: id.Shape()}
+		}
+	}
+
+	var (
+		positives []string
+		first     error
+	)
+	for _, t := range p.terms {
+		if t.negate {
+			continue
+		}
+		err := t.explain(id)
+		if err == nil {
+			return nil
+		}
+		if first == nil {
+			first = err
+		}
+		positives = append(positives, t.format())
+	}
+
+	switch len(positives) {
+	case 0:
+		return nil
+	case 1:
+		return first
+	default:
+		return &MismatchError{ID: id, Part: PartPattern, Want: strings.Join(positives, alternation), Got: id.Shape()}
+	}
+}
+
+// Match returns true if the id matches the pattern
+func (p Pattern) Match(id ID) bool {
+	return p.Explain(id) == nil
+}
+
+// Parent returns the pattern for the logical parent of the first alternative e.g. project/contract#change => project/contract,
+// entity#account/ar => entity#account
+func (p Pattern) Parent() Pattern {
+	shape, path := p.Shape(), p.PathSegments()
+	switch {
+	case len(path) > 0:
+		return newShapePattern(shape[0], shape[1], path[:len(path)-1])
+	case shape[1] != "":
+		return newShapePattern(shape[0], strings.TrimSuffix(strings.TrimSuffix(shape[1], lastChild(shape[1])), "/"), nil)
+	default:
+		return newShapePattern("", "", nil)
+	}
+}
+
+// Shape returns the first alternative as a shape slice with 3 elements, the last being the path head; see ShapeSlice
+// and PathSegments
+func (p Pattern) Shape() []string {
+	if len(p.terms) == 0 {
+		return make([]string, 3)
+	}
+	t := p.terms[0]
+	shape := []string{t.parent, strings.Join(t.children, "/"), ""}
+	if len(t.path) > 0 {
+		shape[2] = t.path[0]
+	}
+	return shape
+}
+
+// PathSegments returns the path segments the first alternative requires the path to begin with
+// e.g. entity#account/ar => [account ar]
+func (p Pattern) PathSegments() []string {
+	if len(p.terms) == 0 {
+		return nil
+	}
+	return append([]string(nil), p.terms[0].path...)
+}
+
+func (p Pattern) String() string {
+	return p.raw
+}
+
+// explain returns an error describing why the valid id does not match the term, ignoring negation
+func (t term) explain(id ID) error {
+	c := id.components()
+	switch {
+	case !matchPart(t.env, c.Env):
+		return &MismatchError{ID: id, Part: PartEnv, Want: t.env, Got: c.Env}
+	case !matchPart(t.service, c.Service.String()):
+		return &MismatchError{ID: id, Part: PartService, Want: t.service, Got: c.Service.String()}
+	case t.any:
+		return nil
+	}
+
+	shape := id.ShapeSlice()
+	switch {
+	case !matchPart(t.parent, shape[0]):
+		return &MismatchError{ID: id, Part: PartParentType, Want: t.parent, Got: shape[0]}
+	case t.compound && shape[1] == "":
+		return &MismatchError{ID: id, Part: PartChild, Want: wildcard}
+	case !t.compound && shape[1] != "":
+		return &MismatchError{ID: id, Part: PartChild, Got: shape[1]}
+	case len(t.children) > 0 && !matchChildren(t.children, c.Children):
+		return &MismatchError{ID: id, Part: PartChildType, Want: strings.Join(t.children, "/"), Got: shape[1]}
+	case t.hasPath && !id.HasPath():
+		return &MismatchError{ID: id, Part: PartPath, Want: wildcard}
+	case !t.hasPath && id.HasPath():
+		return &MismatchError{ID: id, Part: PartPath, Got: id.String()[len(id.Base()):]}
+	case len(t.path) == 1 && !matchPart(t.path[0], shape[2]):
+		return &MismatchError{ID: id, Part: PartPathHead, Want: t.path[0], Got: shape[2]}
+	case len(t.path) > 1 && !matchPath(t.path, c.Path):
+		return &MismatchError{ID: id, Part: PartPath, Want: strings.Join(t.path, pathSep), Got: strings.Join(c.Path, pathSep)}
+	}
+
+	return nil
+}
+
+// format renders the term in its canonical form
+func (t term) format() string {
+	var s string
+	if t.negate {
+		s += negation
+	}
+	if t.env != "" {
+		s += t.env + sep
+	}
+	if t.service != "" || t.env != "" {
+		s += t.service + sep
+	}
+	if t.any {
+		if s == "" {
+			return ""
+		}
+		return s + anyShape
+	}
+
+	s += t.parent
+	if t.compound {
+		s += "/" + strings.Join(t.children, "/")
+	}
+	if t.hasPath {
+		s += "#" + strings.Join(t.path, pathSep)
+	}
+	return s
+}
+
+// matchPart returns true if the pattern part, want, matches got; blank and wildcard match anything
+func matchPart(want, got string) bool {
+	return want == "" || want == wildcard || want == got
+}
+
+// matchPath returns true if got begins with the segments of want
+func matchPath(want, got []string) bool {
+	if len(want) > len(got) {
+		return false
+	}
+	for i, segment := range want {
+		if !matchPart(segment, got[i]) {
+			return false
+		}
+	}
+	return true
+}
+
+func matchChildren(want []string, got []Segment) bool {
+	if len(want) != len(got) {
+		return false
+	}
+	for i, child := range got {
+		if !matchPart(want[i], child.Type.String()) {
+			return false
+		}
+	}
+	return true
+}
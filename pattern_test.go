@@ -0,0 +1,378 @@
+package frn
+
+import (
+	"testing"
+
+	"github.com/tj/assert"
+)
+
+func TestCompilePattern(t *testing.T) {
+	testCases := map[string]struct {
+		Pattern   string
+		WantShape []string
+		WantErr   bool
+	}{
+		"empty": {
+			Pattern:   "",
+			WantShape: []string{"", "", ""},
+		},
+		"unary": {
+			Pattern:   "project",
+			WantShape: []string{"project", "", ""},
+		},
+		"binary": {
+			Pattern:   "project/contract",
+			WantShape: []string{"project", "contract", ""},
+		},
+		"tertiary": {
+			Pattern:   "project/contract#change",
+			WantShape: []string{"project", "contract", "change"},
+		},
+		"tertiary - alt": {
+			Pattern:   "project#change",
+			WantShape: []string{"project", "", "change"},
+		},
+		"grandchild": {
+			Pattern:   "project/contract/approval#change",
+			WantShape: []string{"project", "contract/approval", "change"},
+		},
+		"dashes": {
+			Pattern:   "card-tx/fund-request",
+			WantShape: []string{"card-tx", "fund-request", ""},
+		},
+		"any child": {
+			Pattern:   "project/",
+			WantShape: []string{"project", "", ""},
+		},
+		"any parent": {
+			Pattern:   "/contract",
+			WantShape: []string{"", "contract", ""},
+		},
+		"bad character": {
+			Pattern: "project.contract",
+			WantErr: true,
+		},
+		"missing child": {
+			Pattern: "project/contract/",
+			WantErr: true,
+		},
+		"path segments": {
+			Pattern:   "entity#account/ar",
+			WantShape: []string{"entity", "", "account"},
+		},
+		"multiple paths": {
+			Pattern: "project#a#b",
+			WantErr: true,
+		},
+		"alternation": {
+			Pattern:   "project/contract|!crm:entity",
+			WantShape: []string{"project", "contract", ""},
+		},
+		"qualified": {
+			Pattern:   "dev:crm:*/contract",
+			WantShape: []string{"*", "contract", ""},
+		},
+		"empty alternative": {
+			Pattern: "project|",
+			WantErr: true,
+		},
+		"too many qualifiers": {
+			Pattern: "a:b:c:project",
+			WantErr: true,
+		},
+		"partial wildcard": {
+			Pattern: "pro*",
+			WantErr: true,
+		},
+	}
+
+	for label, tc := range testCases {
+		t.Run(label, func(t *testing.T) {
+			got, err := CompilePattern(tc.Pattern)
+			if tc.WantErr {
+				assert.NotNil(t, err)
+				return
+			}
+			assert.Nil(t, err)
+			assert.Equal(t, tc.WantShape, got.Shape())
+			assert.Equal(t, tc.Pattern, got.String())
+		})
+	}
+}
+
+func TestPattern_Explain(t *testing.T) {
+	testCases := map[string]struct {
+		Pattern string
+		ID      ID
+		WantErr string
+	}{
+		"ok": {
+			Pattern: "project/contract#key",
+			ID:      "fm:crm:project:1:contract:2/key/value",
+		},
+		"any": {
+			Pattern: "",
+			ID:      "fm:crm:project:1",
+		},
+		"any - invalid": {
+			Pattern: "",
+			ID:      "blah",
+			WantErr: `invalid frn, "blah": missing service at offset 4`,
+		},
+		"invalid": {
+			Pattern: "project",
+			ID:      "fm:crm:project:1.2",
+			WantErr: `invalid frn, "fm:crm:project:1.2": invalid character '.' in value at offset 16`,
+		},
+		"parent": {
+			Pattern: "project",
+			ID:      "fm:crm:entity:1",
+			WantErr: "frn fm:crm:entity:1: parent type mismatch: want project, got entity",
+		},
+		"path segments": {
+			Pattern: "entity#account/ar",
+			ID:      "fm:fin:entity:1/account/ap",
+			WantErr: "frn fm:fin:entity:1/account/ap: path mismatch: want account/ar, got account/ap",
+		},
+		"missing child": {
+			Pattern: "project/",
+			ID:      "fm:crm:project:1",
+			WantErr: "frn fm:crm:project:1: missing child",
+		},
+		"unexpected child": {
+			Pattern: "project",
+			ID:      "fm:crm:project:1:contract:2",
+			WantErr: "frn fm:crm:project:1:contract:2: unexpected child, contract",
+		},
+		"child": {
+			Pattern: "project/contract",
+			ID:      "fm:crm:project:1:entity:2",
+			WantErr: "frn fm:crm:project:1:entity:2: child type mismatch: want contract, got entity",
+		},
+		"missing path": {
+			Pattern: "project#key",
+			ID:      "fm:crm:project:1",
+			WantErr: "frn fm:crm:project:1: missing path",
+		},
+		"unexpected path": {
+			Pattern: "project",
+			ID:      "fm:crm:project:1/key",
+			WantErr: "frn fm:crm:project:1/key: unexpected path, /key",
+		},
+		"path head": {
+			Pattern: "project#key",
+			ID:      "fm:crm:project:1/other",
+			WantErr: "frn fm:crm:project:1/other: path head mismatch: want key, got other",
+		},
+		"service": {
+			Pattern: "fin:project",
+			ID:      "fm:crm:project:1",
+			WantErr: "frn fm:crm:project:1: service mismatch: want fin, got crm",
+		},
+		"alternation": {
+			Pattern: "project|crm:contract",
+			ID:      "fm:crm:entity:1",
+			WantErr: "frn fm:crm:entity:1: pattern mismatch: want project|crm:contract, got entity",
+		},
+		"negation": {
+			Pattern: "project/|!project/event",
+			ID:      "fm:crm:project:1:event:2",
+			WantErr: "frn fm:crm:project:1:event:2: pattern mismatch: want !project/event, got project/event",
+		},
+		"negation only - invalid": {
+			Pattern: "!dev:*:**",
+			ID:      "blah",
+			WantErr: `invalid frn, "blah": missing service at offset 4`,
+		},
+	}
+
+	for label, tc := range testCases {
+		t.Run(label, func(t *testing.T) {
+			p := MustCompilePattern(tc.Pattern)
+			err := p.Explain(tc.ID)
+			if tc.WantErr == "" {
+				assert.Nil(t, err)
+				assert.True(t, p.Match(tc.ID))
+				return
+			}
+			assert.EqualError(t, err, tc.WantErr)
+			assert.False(t, p.Match(tc.ID))
+		})
+	}
+}
+
+func TestPattern_Match(t *testing.T) {
+	testCases := map[string]struct {
+		Pattern string
+		ID      ID
+		Want    bool
+	}{
+		"alternation - first": {
+			Pattern: "project|contract",
+			ID:      "fm:crm:project:1",
+			Want:    true,
+		},
+		"alternation - second": {
+			Pattern: "project|contract",
+			ID:      "fm:crm:contract:1",
+			Want:    true,
+		},
+		"alternation - neither": {
+			Pattern: "project|contract",
+			ID:      "fm:crm:entity:1",
+			Want:    false,
+		},
+		"wildcard parent": {
+			Pattern: "*/contract",
+			ID:      "fm:crm:entity:1:contract:2",
+			Want:    true,
+		},
+		"wildcard child": {
+			Pattern: "project/*/approval",
+			ID:      "fm:crm:project:1:contract:2:approval:3",
+			Want:    true,
+		},
+		"wildcard child - too shallow": {
+			Pattern: "project/*/approval",
+			ID:      "fm:crm:project:1:approval:3",
+			Want:    false,
+		},
+		"wildcard head": {
+			Pattern: "project#*",
+			ID:      "fm:crm:project:1/key/value",
+			Want:    true,
+		},
+		"service": {
+			Pattern: "crm:project",
+			ID:      "fm:crm:project:1",
+			Want:    true,
+		},
+		"service - mismatch": {
+			Pattern: "crm:project",
+			ID:      "fm:fin:project:1",
+			Want:    false,
+		},
+		"env": {
+			Pattern: "dev:*:project",
+			ID:      "dev:fin:project:1",
+			Want:    true,
+		},
+		"env - mismatch": {
+			Pattern: "dev:*:project",
+			ID:      "fm:fin:project:1",
+			Want:    false,
+		},
+		"any shape": {
+			Pattern: "crm:**",
+			ID:      "fm:crm:project:1:contract:2/key/value",
+			Want:    true,
+		},
+		"any shape - invalid": {
+			Pattern: "crm:**",
+			ID:      "fm:crm:project",
+			Want:    false,
+		},
+		"negation only": {
+			Pattern: "!dev:*:**",
+			ID:      "fm:crm:project:1",
+			Want:    true,
+		},
+		"negation only - excluded": {
+			Pattern: "!dev:*:**",
+			ID:      "dev:crm:project:1",
+			Want:    false,
+		},
+		"negation only - invalid": {
+			Pattern: "!dev:*:**",
+			ID:      "blah",
+			Want:    false,
+		},
+		"any child except": {
+			Pattern: "project/*|!project/event",
+			ID:      "fm:crm:project:1:contract:2",
+			Want:    true,
+		},
+		"any child except - excluded": {
+			Pattern: "project/*|!project/event",
+			ID:      "fm:crm:project:1:event:2",
+			Want:    false,
+		},
+		"path prefix": {
+			Pattern: "entity#account/ar",
+			ID:      "fm:fin:entity:1/account/ar/2024/q3",
+			Want:    true,
+		},
+		"path prefix - exact": {
+			Pattern: "entity#account/ar",
+			ID:      "fm:fin:entity:1/account/ar",
+			Want:    true,
+		},
+		"path prefix - mismatch": {
+			Pattern: "entity#account/ar",
+			ID:      "fm:fin:entity:1/account/ap/2024",
+			Want:    false,
+		},
+		"path prefix - too short": {
+			Pattern: "entity#account/ar",
+			ID:      "fm:fin:entity:1/account",
+			Want:    false,
+		},
+		"path wildcard segment": {
+			Pattern: "entity#account/*/2024",
+			ID:      "fm:fin:entity:1/account/ap/2024/q3",
+			Want:    true,
+		},
+	}
+
+	for label, tc := range testCases {
+		t.Run(label, func(t *testing.T) {
+			got := MustCompilePattern(tc.Pattern).Match(tc.ID)
+			assert.Equal(t, tc.Want, got)
+		})
+	}
+}
+
+func TestPattern_Parent(t *testing.T) {
+	testCases := map[string]struct {
+		Pattern string
+		Want    string
+	}{
+		"unary": {
+			Pattern: "project",
+			Want:    "",
+		},
+		"binary": {
+			Pattern: "project/contract",
+			Want:    "project",
+		},
+		"grandchild": {
+			Pattern: "project/contract/approval",
+			Want:    "project/contract",
+		},
+		"tertiary": {
+			Pattern: "project/contract#change",
+			Want:    "project/contract",
+		},
+		"path segments": {
+			Pattern: "entity#account/ar",
+			Want:    "entity#account",
+		},
+		"path head": {
+			Pattern: "entity#account",
+			Want:    "entity",
+		},
+	}
+
+	for label, tc := range testCases {
+		t.Run(label, func(t *testing.T) {
+			got := MustCompilePattern(tc.Pattern).Parent()
+			assert.Equal(t, tc.Want, got.String())
+		})
+	}
+}
+
+func TestPattern_PathSegments(t *testing.T) {
+	assert.Equal(t, []string{"account", "ar"}, MustCompilePattern("entity/ledger#account/ar").PathSegments())
+	assert.Equal(t, []string{"account"}, MustCompilePattern("entity#account").PathSegments())
+	assert.Nil(t, MustCompilePattern("entity").PathSegments())
+}
@@ -0,0 +1,56 @@
+package frn
+
+// Effect is the outcome of a policy Statement
+type Effect string
+
+const (
+	Allow Effect = "allow"
+	Deny  Effect = "deny"
+)
+
+// Statement allows or denies the principals access to the resources
+type Statement struct {
+	Effect     Effect
+	Principals []Glob // Principals the statement applies to; empty applies to any principal
+	Resources  []Glob // Resources the statement applies to
+}
+
+// Match returns true if the statement applies to the principal and resource
+func (s Statement) Match(principal, resource ID) bool {
+	return (len(s.Principals) == 0 || matchAnyGlob(s.Principals, principal)) && matchAnyGlob(s.Resources, resource)
+}
+
+// Policy is a set of statements evaluated with deny-overrides: access is denied if any matching statement denies it,
+// allowed if any matching statement allows it, and otherwise denied
+type Policy []Statement
+
+// Evaluate returns the effect of the policy for the principal and resource
+func (p Policy) Evaluate(principal, resource ID) Effect {
+	effect := Deny
+	for _, s := range p {
+		if !s.Match(principal, resource) {
+			continue
+		}
+		switch s.Effect {
+		case Deny:
+			return Deny
+		case Allow:
+			effect = Allow
+		}
+	}
+	return effect
+}
+
+// Allowed returns true if the policy allows the principal access to the resource
+func (p Policy) Allowed(principal, resource ID) bool {
+	return p.Evaluate(principal, resource) == Allow
+}
+
+func matchAnyGlob(globs []Glob, id ID) bool {
+	for _, g := range globs {
+		if g.Match(id) {
+			return true
+		}
+	}
+	return false
+}
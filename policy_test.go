@@ -0,0 +1,74 @@
+package frn
+
+import (
+	"testing"
+
+	"github.com/tj/assert"
+)
+
+func TestPolicy_Evaluate(t *testing.T) {
+	var (
+		admin  = ID("fm:iam:user:admin")
+		member = ID("fm:iam:user:member")
+		policy = Policy{
+			{
+				Effect:     Allow,
+				Principals: []Glob{MustCompileGlob("fm:iam:user:*")},
+				Resources:  []Glob{MustCompileGlob("fm:crm:project:*:**")},
+			},
+			{
+				Effect:     Deny,
+				Principals: []Glob{MustCompileGlob("fm:iam:user:member")},
+				Resources:  []Glob{MustCompileGlob("fm:crm:project:*:contract:*")},
+			},
+			{
+				Effect:    Deny,
+				Resources: []Glob{MustCompileGlob("fm:crm:project:archived:**")},
+			},
+		}
+	)
+
+	testCases := map[string]struct {
+		Principal ID
+		Resource  ID
+		Want      Effect
+	}{
+		"allow": {
+			Principal: member,
+			Resource:  "fm:crm:project:1",
+			Want:      Allow,
+		},
+		"allow - descendant": {
+			Principal: admin,
+			Resource:  "fm:crm:project:1:contract:2",
+			Want:      Allow,
+		},
+		"deny overrides allow": {
+			Principal: member,
+			Resource:  "fm:crm:project:1:contract:2",
+			Want:      Deny,
+		},
+		"deny - any principal": {
+			Principal: admin,
+			Resource:  "fm:crm:project:archived",
+			Want:      Deny,
+		},
+		"implicit deny - resource": {
+			Principal: admin,
+			Resource:  "fm:fin:entity:1",
+			Want:      Deny,
+		},
+		"implicit deny - principal": {
+			Principal: "fm:iam:group:1",
+			Resource:  "fm:crm:project:1",
+			Want:      Deny,
+		},
+	}
+
+	for label, tc := range testCases {
+		t.Run(label, func(t *testing.T) {
+			assert.Equal(t, tc.Want, policy.Evaluate(tc.Principal, tc.Resource))
+			assert.Equal(t, tc.Want == Allow, policy.Allowed(tc.Principal, tc.Resource))
+		})
+	}
+}
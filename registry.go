@@ -0,0 +1,128 @@
+package frn
+
+import (
+	"fmt"
+	"slices"
+	"sort"
+	"sync"
+)
+
+// TypeSpec describes where a type may appear within an id
+type TypeSpec struct {
+	Type      Type
+	Parents   []Type   // Parents the type may be nested under; a type without parents may only appear as the root
+	Root      bool     // Root additionally permits the type to appear as the root when Parents are set
+	PathHeads []string // PathHeads that may follow the type e.g. account; an id whose innermost type has no path heads may not have a path
+}
+
+// SchemaError describes why a valid id does not conform to the schema described by a Registry
+type SchemaError struct {
+	ID     ID
+	Reason string // Reason the id was rejected e.g. type, contract, must be nested under one of [project]
+}
+
+func (e *SchemaError) Error() string {
+	return fmt.Sprintf("frn %v: %v", e.ID, e.Reason)
+}
+
+// Registry describes the schema of ids allowed for each service
+type Registry struct {
+	mu       sync.RWMutex
+	services map[Service]map[Type]TypeSpec
+}
+
+func NewRegistry() *Registry {
+	return &Registry{
+		services: map[Service]map[Type]TypeSpec{},
+	}
+}
+
+// Register adds the provided types to the service, replacing any previously registered spec for the same type
+func (r *Registry) Register(s Service, specs ...TypeSpec) *Registry {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	types, ok := r.services[s]
+	if !ok {
+		types = map[Type]TypeSpec{}
+		r.services[s] = types
+	}
+	for _, spec := range specs {
+		types[spec.Type] = spec
+	}
+
+	return r
+}
+
+// Lookup returns the spec registered for the type within the service
+func (r *Registry) Lookup(s Service, t Type) (TypeSpec, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	spec, ok := r.services[s][t]
+	return spec, ok
+}
+
+// Types returns the types registered for the service in lexical order
+func (r *Registry) Types(s Service) []Type {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	var types []Type
+	for t := range r.services[s] {
+		types = append(types, t)
+	}
+	sort.Slice(types, func(i, j int) bool { return types[i] < types[j] })
+	return types
+}
+
+// Check returns a *ParseError if the id is not valid or a *SchemaError if it does not conform to the registered schema
+// e.g. fm:crm:contract:1 is rejected if contracts may only be nested under projects
+func (r *Registry) Check(id ID) error {
+	c, err := Parse(id.String())
+	if err != nil {
+		return err
+	}
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	types, ok := r.services[c.Service]
+	if !ok {
+		return &SchemaError{ID: id, Reason: fmt.Sprintf("service, %v, not registered", c.Service)}
+	}
+
+	spec, ok := types[c.Type]
+	if !ok {
+		return &SchemaError{ID: id, Reason: fmt.Sprintf("type, %v, not registered for service, %v", c.Type, c.Service)}
+	}
+	if len(spec.Parents) > 0 && !spec.Root {
+		return &SchemaError{ID: id, Reason: fmt.Sprintf("type, %v, must be nested under one of %v", c.Type, spec.Parents)}
+	}
+
+	for _, child := range c.Children {
+		parent := spec.Type
+		spec, ok = types[child.Type]
+		if !ok {
+			return &SchemaError{ID: id, Reason: fmt.Sprintf("type, %v, not registered for service, %v", child.Type, c.Service)}
+		}
+		if !slices.Contains(spec.Parents, parent) {
+			return &SchemaError{ID: id, Reason: fmt.Sprintf("type, %v, may not be nested under %v", child.Type, parent)}
+		}
+	}
+
+	if len(c.Path) > 0 && !slices.Contains(spec.PathHeads, c.Path[0]) {
+		return &SchemaError{ID: id, Reason: fmt.Sprintf("path head, %v, not permitted for type, %v", c.Path[0], spec.Type)}
+	}
+
+	return nil
+}
+
+// SampleViaShape is the same as SampleViaShape, but only returns ids that conform to the registry
+func (r *Registry) SampleViaShape(ns Namespace, potentialParentID ID, s string) (ID, bool) {
+	id, ok := SampleViaShape(ns, potentialParentID, s)
+	if !ok || r.Check(id) != nil {
+		return "", false
+	}
+	return id, true
+}
@@ -0,0 +1,121 @@
+package frn
+
+import (
+	"testing"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/tj/assert"
+)
+
+const (
+	TypeApproval Type = "approval"
+	TypeContract Type = "contract"
+)
+
+func newTestRegistry() *Registry {
+	return NewRegistry().Register(ServiceCRM,
+		TypeSpec{Type: TypeProject, PathHeads: []string{"account"}},
+		TypeSpec{Type: TypeContract, Parents: []Type{TypeProject}},
+		TypeSpec{Type: TypeApproval, Parents: []Type{TypeContract}, PathHeads: []string{"step"}},
+		TypeSpec{Type: TypeEntity, Parents: []Type{TypeProject}, Root: true},
+	)
+}
+
+func TestRegistry_Check(t *testing.T) {
+	testCases := map[string]struct {
+		ID      ID
+		WantErr bool
+	}{
+		"root": {
+			ID: "fm:crm:project:1",
+		},
+		"root with path": {
+			ID: "fm:crm:project:1/account/ar",
+		},
+		"root with bad path": {
+			ID:      "fm:crm:project:1/other/ar",
+			WantErr: true,
+		},
+		"child only type at root": {
+			ID:      "fm:crm:contract:1",
+			WantErr: true,
+		},
+		"child": {
+			ID: "fm:crm:project:1:contract:2",
+		},
+		"child without path heads": {
+			ID:      "fm:crm:project:1:contract:2/account/ar",
+			WantErr: true,
+		},
+		"grandchild with path": {
+			ID: "fm:crm:project:1:contract:2:approval:3/step/1",
+		},
+		"grandchild under wrong parent": {
+			ID:      "fm:crm:project:1:approval:3",
+			WantErr: true,
+		},
+		"root and child": {
+			ID: "fm:crm:entity:1",
+		},
+		"root and child - child": {
+			ID: "fm:crm:project:1:entity:2",
+		},
+		"unknown type": {
+			ID:      "fm:crm:other:1",
+			WantErr: true,
+		},
+		"unknown service": {
+			ID:      "fm:fin:project:1",
+			WantErr: true,
+		},
+		"invalid": {
+			ID:      "blah",
+			WantErr: true,
+		},
+	}
+
+	registry := newTestRegistry()
+	for label, tc := range testCases {
+		t.Run(label, func(t *testing.T) {
+			err := registry.Check(tc.ID)
+			if tc.WantErr {
+				assert.NotNil(t, err)
+			} else {
+				assert.Nil(t, err)
+			}
+		})
+	}
+}
+
+func TestRegistry_Types(t *testing.T) {
+	registry := newTestRegistry()
+	assert.Equal(t, []Type{TypeApproval, TypeContract, TypeEntity, TypeProject}, registry.Types(ServiceCRM))
+	assert.Nil(t, registry.Types(ServiceFinance))
+}
+
+func TestRegistry_SampleViaShape(t *testing.T) {
+	var (
+		registry = newTestRegistry()
+		ns       = NewNamespace("dev", ServiceCRM)
+	)
+
+	got, ok := registry.SampleViaShape(ns, "dev:crm:project:1", "project/contract")
+	assert.True(t, ok)
+	assert.Equal(t, ID("dev:crm:project:1:contract:_"), got)
+
+	_, ok = registry.SampleViaShape(ns, "dev:crm:project:1:contract:2", "project/contract#account")
+	assert.False(t, ok)
+}
+
+func TestRegisterValidation_WithRegistry(t *testing.T) {
+	validate := validator.New()
+	RegisterValidation(validate, WithRegistry(newTestRegistry()))
+
+	type Example struct {
+		Value ID `validate:"frn"`
+	}
+
+	assert.Nil(t, validate.Struct(Example{Value: ""}))
+	assert.Nil(t, validate.Struct(Example{Value: "fm:crm:project:1:contract:2"}))
+	assert.NotNil(t, validate.Struct(Example{Value: "fm:crm:contract:2"}))
+}
@@ -0,0 +1,169 @@
+package frn
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+)
+
+var (
+	// ErrNoResolver is returned when no resolver is registered for the service and type of an id
+	ErrNoResolver = errors.New("no resolver registered")
+	// ErrNotFound is returned when a batch resolver does not return a value for an id
+	ErrNotFound = errors.New("not found")
+)
+
+// ResolveFunc loads the entity identified by id
+type ResolveFunc func(ctx context.Context, id ID) (any, error)
+
+// ResolveBatchFunc loads the entities identified by ids, all of the same service and type. ids missing from the
+// returned map are reported as ErrNotFound; an error is reported for every id.
+type ResolveBatchFunc func(ctx context.Context, ids IDSet) (map[ID]any, error)
+
+// Result holds the outcome of resolving a single id
+type Result struct {
+	ID    ID
+	Value any
+	Err   error
+}
+
+// resolverKey identifies the handler for an id by service and innermost type
+type resolverKey struct {
+	service Service
+	t       Type
+}
+
+type resolverHandler struct {
+	fn    ResolveFunc
+	batch ResolveBatchFunc
+}
+
+// Resolver loads entities by id, dispatching to the handler registered for the service and innermost type of the id
+// e.g. fm:crm:project:1:contract:2 is resolved by the handler for crm, contract
+type Resolver struct {
+	mu       sync.RWMutex
+	handlers map[resolverKey]resolverHandler
+}
+
+func NewResolver() *Resolver {
+	return &Resolver{
+		handlers: map[resolverKey]resolverHandler{},
+	}
+}
+
+// Register sets the handler for the service and type, replacing any previously registered handler
+func (r *Resolver) Register(s Service, t Type, fn ResolveFunc) *Resolver {
+	return r.register(s, t, resolverHandler{fn: fn})
+}
+
+// RegisterBatch sets the batch handler for the service and type, replacing any previously registered handler
+func (r *Resolver) RegisterBatch(s Service, t Type, fn ResolveBatchFunc) *Resolver {
+	return r.register(s, t, resolverHandler{batch: fn})
+}
+
+func (r *Resolver) register(s Service, t Type, h resolverHandler) *Resolver {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.handlers[resolverKey{service: s, t: t}] = h
+	return r
+}
+
+// lookup returns the key and handler for the id
+func (r *Resolver) lookup(id ID) (resolverKey, resolverHandler, error) {
+	c, err := Parse(id.String())
+	if err != nil {
+		return resolverKey{}, resolverHandler{}, err
+	}
+
+	key := resolverKey{service: c.Service, t: c.leaf().Type}
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	h, ok := r.handlers[key]
+	if !ok {
+		return resolverKey{}, resolverHandler{}, fmt.Errorf("frn %v: %w for %v:%v", id, ErrNoResolver, key.service, key.t)
+	}
+	return key, h, nil
+}
+
+// Resolve loads the entity identified by id
+func (r *Resolver) Resolve(ctx context.Context, id ID) (any, error) {
+	results := r.ResolveMany(ctx, IDSet{id})
+	return results[0].Value, results[0].Err
+}
+
+// ResolveMany loads the entities identified by ids, returning a Result for each id in the same order.
+// ids are grouped by service and type and each group is loaded concurrently; batch handlers are called once per group.
+func (r *Resolver) ResolveMany(ctx context.Context, ids IDSet) []Result {
+	var (
+		results = make([]Result, len(ids))
+		groups  = map[resolverKey][]int{}
+		order   []resolverKey
+		batches = map[resolverKey]ResolveBatchFunc{}
+		wg      sync.WaitGroup
+	)
+	for i, id := range ids {
+		results[i].ID = id
+
+		key, h, err := r.lookup(id)
+		if err != nil {
+			results[i].Err = err
+			continue
+		}
+
+		if h.batch == nil {
+			wg.Add(1)
+			go func(i int, fn ResolveFunc) {
+				defer wg.Done()
+				results[i].Value, results[i].Err = fn(ctx, results[i].ID)
+			}(i, h.fn)
+			continue
+		}
+
+		if _, ok := groups[key]; !ok {
+			order = append(order, key)
+			batches[key] = h.batch
+		}
+		groups[key] = append(groups[key], i)
+	}
+
+	for _, key := range order {
+		wg.Add(1)
+		go func(fn ResolveBatchFunc, indexes []int) {
+			defer wg.Done()
+			resolveBatch(ctx, fn, results, indexes)
+		}(batches[key], groups[key])
+	}
+
+	wg.Wait()
+	return results
+}
+
+// resolveBatch loads the results at indexes with fn, requesting each distinct id once
+func resolveBatch(ctx context.Context, fn ResolveBatchFunc, results []Result, indexes []int) {
+	var (
+		batch IDSet
+		seen  = map[ID]struct{}{}
+	)
+	for _, i := range indexes {
+		if _, ok := seen[results[i].ID]; !ok {
+			seen[results[i].ID] = struct{}{}
+			batch = append(batch, results[i].ID)
+		}
+	}
+
+	values, err := fn(ctx, batch)
+	for _, i := range indexes {
+		switch v, ok := values[results[i].ID]; {
+		case err != nil:
+			results[i].Err = err
+		case !ok:
+			results[i].Err = fmt.Errorf("frn %v: %w", results[i].ID, ErrNotFound)
+		default:
+			results[i].Value = v
+		}
+	}
+}
@@ -0,0 +1,116 @@
+package frn
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+
+	"github.com/tj/assert"
+)
+
+func TestResolver_ResolveMany(t *testing.T) {
+	var (
+		mu      sync.Mutex
+		batches []IDSet
+		errBoom = errors.New("boom")
+	)
+	resolver := NewResolver().
+		Register(ServiceCRM, TypeProject, func(ctx context.Context, id ID) (any, error) {
+			if id.Value() == "missing" {
+				return nil, ErrNotFound
+			}
+			return "project " + id.Value(), nil
+		}).
+		RegisterBatch(ServiceCRM, TypeContract, func(ctx context.Context, ids IDSet) (map[ID]any, error) {
+			mu.Lock()
+			batches = append(batches, ids)
+			mu.Unlock()
+
+			values := map[ID]any{}
+			for _, id := range ids {
+				if id.Child().Value() != "missing" {
+					values[id] = "contract " + id.Child().Value()
+				}
+			}
+			return values, nil
+		}).
+		RegisterBatch(ServiceCRM, TypeApproval, func(ctx context.Context, ids IDSet) (map[ID]any, error) {
+			return nil, errBoom
+		})
+
+	testCases := map[string]struct {
+		ID        ID
+		WantValue any
+		WantErr   error
+	}{
+		"single": {
+			ID:        "fm:crm:project:1",
+			WantValue: "project 1",
+		},
+		"single - not found": {
+			ID:      "fm:crm:project:missing",
+			WantErr: ErrNotFound,
+		},
+		"batch": {
+			ID:        "fm:crm:project:1:contract:2",
+			WantValue: "contract 2",
+		},
+		"batch - with path": {
+			ID:        "fm:crm:project:1:contract:3/account",
+			WantValue: "contract 3",
+		},
+		"batch - not found": {
+			ID:      "fm:crm:project:1:contract:missing",
+			WantErr: ErrNotFound,
+		},
+		"batch - error": {
+			ID:      "fm:crm:project:1:approval:1",
+			WantErr: errBoom,
+		},
+		"no resolver": {
+			ID:      "fm:fin:project:1",
+			WantErr: ErrNoResolver,
+		},
+	}
+
+	var ids IDSet
+	for _, tc := range testCases {
+		ids = append(ids, tc.ID)
+	}
+	ids = append(ids, "fm:crm:project:1:contract:2") // duplicates are requested once
+
+	results := resolver.ResolveMany(context.Background(), ids)
+	assert.Len(t, results, len(ids))
+	assert.Len(t, batches, 1)
+	assert.Len(t, batches[0], 3)
+
+	byID := map[ID]Result{}
+	for i, result := range results {
+		assert.Equal(t, ids[i], result.ID)
+		byID[result.ID] = result
+	}
+
+	for label, tc := range testCases {
+		t.Run(label, func(t *testing.T) {
+			result := byID[tc.ID]
+			assert.Equal(t, tc.WantValue, result.Value)
+			assert.True(t, errors.Is(result.Err, tc.WantErr), "want %v, got %v", tc.WantErr, result.Err)
+		})
+	}
+}
+
+func TestResolver_Resolve(t *testing.T) {
+	resolver := NewResolver().
+		RegisterBatch(ServiceCRM, TypeProject, func(ctx context.Context, ids IDSet) (map[ID]any, error) {
+			return map[ID]any{ids[0]: len(ids)}, nil
+		})
+
+	v, err := resolver.Resolve(context.Background(), "fm:crm:project:1")
+	assert.Nil(t, err)
+	assert.Equal(t, 1, v)
+
+	_, err = resolver.Resolve(context.Background(), "fm:crm:project")
+	var pe *ParseError
+	assert.True(t, errors.As(err, &pe))
+}
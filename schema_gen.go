@@ -0,0 +1,14 @@
+// Code generated by frngen from schema.yaml; DO NOT EDIT.
+
+package frn
+
+const (
+	ServiceAgentDB    Service = "adb"
+	ServiceCRM        Service = "crm"
+	ServiceFinance    Service = "fin"
+	ServiceOnboarding Service = "onboarding"
+	ServicePOC        Service = "poc"
+	ServiceRemie      Service = "remie"
+	ServiceRenie      Service = "renie"
+	ServiceSystem     Service = "system"
+)
@@ -0,0 +1,161 @@
+package frn
+
+import (
+	"sort"
+)
+
+// Map returns an index of the ids for constant time membership checks
+//
+//goland:noinspection GoMixedReceiverTypes
+func (vv IDSet) Map() IDMap {
+	m := make(IDMap, len(vv))
+	for _, v := range vv {
+		m[v] = struct{}{}
+	}
+	return m
+}
+
+// Dedupe returns a new IDSet with duplicate ids removed, retaining the first occurrence of each
+//
+//goland:noinspection GoMixedReceiverTypes
+func (vv IDSet) Dedupe() IDSet {
+	return vv.Union()
+}
+
+// Union returns a new IDSet containing the distinct ids of the set followed by those of others, in order of occurrence
+//
+//goland:noinspection GoMixedReceiverTypes
+func (vv IDSet) Union(others ...IDSet) IDSet {
+	var (
+		seen   = IDMap{}
+		result IDSet
+	)
+	for _, set := range append([]IDSet{vv}, others...) {
+		for _, v := range set {
+			if seen.Contains(v) {
+				continue
+			}
+			seen[v] = struct{}{}
+			result = append(result, v)
+		}
+	}
+	return result
+}
+
+// Intersect returns a new IDSet containing the distinct ids of the set that are also in other
+//
+//goland:noinspection GoMixedReceiverTypes
+func (vv IDSet) Intersect(other IDSet) IDSet {
+	index := other.Map()
+	return vv.Dedupe().Where(index.Contains)
+}
+
+// Difference returns a new IDSet containing the distinct ids of the set that are not in other
+//
+//goland:noinspection GoMixedReceiverTypes
+func (vv IDSet) Difference(other IDSet) IDSet {
+	index := other.Map()
+	return vv.Dedupe().Where(func(id ID) bool { return !index.Contains(id) })
+}
+
+// Diff returns the non-blank ids that are in other, but not the set, and those in the set, but not other e.g. to
+// update a stored set, before.Diff(after)
+//
+//goland:noinspection GoMixedReceiverTypes
+func (vv IDSet) Diff(other IDSet) (added, removed IDSet) {
+	before, after := vv.Trim(), other.Trim()
+	return after.Difference(before), before.Difference(after)
+}
+
+// Sort returns a new IDSet sorted in lexical order
+//
+//goland:noinspection GoMixedReceiverTypes
+func (vv IDSet) Sort() IDSet {
+	sorted := append(IDSet(nil), vv...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+	return sorted
+}
+
+// SortByTime returns a new IDSet sorted by the creation time of the KSUID values; see ID.CreatedAt.
+// ids created in the same second, and ids whose values are not KSUIDs, which sort last, are in lexical order.
+//
+//goland:noinspection GoMixedReceiverTypes
+func (vv IDSet) SortByTime() IDSet {
+	sorted := vv.Sort()
+	sort.SliceStable(sorted, func(i, j int) bool {
+		a, aOk := sorted[i].CreatedAt()
+		b, bOk := sorted[j].CreatedAt()
+		if aOk != bOk {
+			return aOk
+		}
+		return a.Before(b)
+	})
+	return sorted
+}
+
+// GroupByType groups the ids by the type of the parent; see ID.Type
+//
+//goland:noinspection GoMixedReceiverTypes
+func (vv IDSet) GroupByType() map[Type]IDSet {
+	groups := map[Type]IDSet{}
+	for _, v := range vv {
+		groups[v.Type()] = append(groups[v.Type()], v)
+	}
+	return groups
+}
+
+// GroupByParent groups the ids by their immediate parent; see ID.Parent
+//
+//goland:noinspection GoMixedReceiverTypes
+func (vv IDSet) GroupByParent() map[ID]IDSet {
+	groups := map[ID]IDSet{}
+	for _, v := range vv {
+		groups[v.Parent()] = append(groups[v.Parent()], v)
+	}
+	return groups
+}
+
+// Parents returns the distinct immediate parents of the ids, in order of occurrence; see ID.Parent
+//
+//goland:noinspection GoMixedReceiverTypes
+func (vv IDSet) Parents() IDSet {
+	var parents IDSet
+	for _, v := range vv {
+		parents = append(parents, v.Parent())
+	}
+	return parents.Dedupe()
+}
+
+// Union returns a new KeySet; see IDSet.Union
+func (kk KeySet) Union(others ...KeySet) KeySet {
+	var sets []IDSet
+	for _, other := range others {
+		sets = append(sets, IDSet(other))
+	}
+	return KeySet(IDSet(kk).Union(sets...))
+}
+
+// Intersect returns a new KeySet; see IDSet.Intersect
+func (kk KeySet) Intersect(other KeySet) KeySet {
+	return KeySet(IDSet(kk).Intersect(IDSet(other)))
+}
+
+// Difference returns a new KeySet; see IDSet.Difference
+func (kk KeySet) Difference(other KeySet) KeySet {
+	return KeySet(IDSet(kk).Difference(IDSet(other)))
+}
+
+// Map returns an index of the keys; see IDSet.Map
+func (kk KeySet) Map() IDMap {
+	return IDSet(kk).Map()
+}
+
+// Sort returns a new KeySet sorted in lexical order
+func (kk KeySet) Sort() KeySet {
+	return KeySet(IDSet(kk).Sort())
+}
+
+// SortByTime returns a new KeySet; see IDSet.SortByTime
+func (kk KeySet) SortByTime() KeySet {
+	return KeySet(IDSet(kk).SortByTime())
+}
@@ -0,0 +1,123 @@
+package frn
+
+import (
+	"testing"
+	"time"
+
+	"github.com/segmentio/ksuid"
+	"github.com/tj/assert"
+)
+
+func TestIDSet_Algebra(t *testing.T) {
+	var (
+		a = IDSet{"fm:crm:project:3", "fm:crm:project:1", "fm:crm:project:2", "fm:crm:project:1"}
+		b = IDSet{"fm:crm:project:2", "fm:crm:project:4"}
+	)
+
+	testCases := map[string]struct {
+		Got  IDSet
+		Want IDSet
+	}{
+		"dedupe": {
+			Got:  a.Dedupe(),
+			Want: IDSet{"fm:crm:project:3", "fm:crm:project:1", "fm:crm:project:2"},
+		},
+		"union": {
+			Got:  a.Union(b),
+			Want: IDSet{"fm:crm:project:3", "fm:crm:project:1", "fm:crm:project:2", "fm:crm:project:4"},
+		},
+		"intersect": {
+			Got:  a.Intersect(b),
+			Want: IDSet{"fm:crm:project:2"},
+		},
+		"difference": {
+			Got:  a.Difference(b),
+			Want: IDSet{"fm:crm:project:3", "fm:crm:project:1"},
+		},
+		"difference - empty": {
+			Got:  b.Difference(a.Union(b)),
+			Want: nil,
+		},
+		"sort": {
+			Got:  a.Sort(),
+			Want: IDSet{"fm:crm:project:1", "fm:crm:project:1", "fm:crm:project:2", "fm:crm:project:3"},
+		},
+		"map slice": {
+			Got:  a.Map().Slice(),
+			Want: IDSet{"fm:crm:project:1", "fm:crm:project:2", "fm:crm:project:3"},
+		},
+		"parents": {
+			Got:  IDSet{"fm:crm:project:1:contract:2", "fm:crm:project:1:contract:3", "fm:crm:project:4/account"}.Parents(),
+			Want: IDSet{"fm:crm:project:1", "fm:crm:project:4"},
+		},
+	}
+
+	for label, tc := range testCases {
+		t.Run(label, func(t *testing.T) {
+			assert.Equal(t, tc.Want, tc.Got)
+		})
+	}
+
+	assert.Equal(t, IDSet{"fm:crm:project:3", "fm:crm:project:1", "fm:crm:project:2", "fm:crm:project:1"}, a, "receiver must not be modified")
+}
+
+func TestIDSet_SortByTime(t *testing.T) {
+	newID := func(at time.Time) ID {
+		k, _ := ksuid.FromParts(at, make([]byte, 16))
+		return NewNamespace("", ServiceCRM).New(TypeProject, k.String())
+	}
+
+	var (
+		now    = time.Now()
+		first  = newID(now.Add(-time.Hour))
+		second = newID(now)
+		other  = ID("fm:crm:project:abc")
+	)
+
+	got := IDSet{other, second, first}.SortByTime()
+	assert.Equal(t, IDSet{first, second, other}, got)
+	assert.Equal(t, KeySet{first, second, other}, KeySet{second, other, first}.SortByTime())
+}
+
+func TestIDSet_Group(t *testing.T) {
+	ids := IDSet{
+		"fm:crm:project:1",
+		"fm:crm:project:1:contract:2",
+		"fm:crm:project:1:contract:3",
+		"fm:crm:entity:4",
+	}
+
+	assert.Equal(t, map[Type]IDSet{
+		TypeProject: {"fm:crm:project:1", "fm:crm:project:1:contract:2", "fm:crm:project:1:contract:3"},
+		TypeEntity:  {"fm:crm:entity:4"},
+	}, ids.GroupByType())
+
+	assert.Equal(t, map[ID]IDSet{
+		"fm:crm:project:1": {"fm:crm:project:1", "fm:crm:project:1:contract:2", "fm:crm:project:1:contract:3"},
+		"fm:crm:entity:4":  {"fm:crm:entity:4"},
+	}, ids.GroupByParent())
+}
+
+func TestKeySet_Algebra(t *testing.T) {
+	var (
+		a = NewKeySet("fm:crm:project:2", "fm:crm:project:1")
+		b = NewKeySet("fm:crm:project:2", "fm:crm:project:3")
+	)
+
+	assert.Equal(t, KeySet{"fm:crm:project:2", "fm:crm:project:1", "fm:crm:project:3"}, a.Union(b))
+	assert.Equal(t, KeySet{"fm:crm:project:2"}, a.Intersect(b))
+	assert.Equal(t, KeySet{"fm:crm:project:1"}, a.Difference(b))
+	assert.Equal(t, KeySet{"fm:crm:project:1", "fm:crm:project:2"}, a.Sort())
+	assert.True(t, a.Map().Contains("fm:crm:project:1"))
+}
+
+func TestIDSet_Diff(t *testing.T) {
+	var (
+		before = IDSet{"fm:crm:contact:1", "fm:crm:contact:2", ""}
+		after  = IDSet{"fm:crm:contact:2", "fm:crm:contact:3", "fm:crm:contact:3"}
+	)
+
+	added, removed := before.Diff(after)
+	assert.Equal(t, IDSet{"fm:crm:contact:3"}, added)
+	assert.Equal(t, IDSet{"fm:crm:contact:1"}, removed)
+}
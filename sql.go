@@ -0,0 +1,160 @@
+package frn
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Scan implements sql.Scanner; NULL scans to an empty id, mirroring Ptr.
+//
+// ID does not implement driver.Valuer as Value already returns the value portion of the id. database/sql
+// writes an ID as a string by default; use Ptr to write an empty id as NULL.
+func (id *ID) Scan(src interface{}) error {
+	s, ok, err := scanString(src)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		*id = ""
+		return nil
+	}
+
+	*id = ID(s)
+
+	return nil
+}
+
+// Value implements driver.Valuer, writing the set as a json array; empty sets are written as NULL
+//
+//goland:noinspection GoMixedReceiverTypes
+func (vv IDSet) Value() (driver.Value, error) {
+	return setValue(vv.Trim())
+}
+
+// Scan implements sql.Scanner, reading either a json array or a postgres array literal e.g. {a,b}
+//
+//goland:noinspection GoMixedReceiverTypes
+func (vv *IDSet) Scan(src interface{}) error {
+	ss, err := scanSet(src)
+	if err != nil {
+		return err
+	}
+
+	*vv = toIDSet(ss)
+
+	return nil
+}
+
+// Value implements driver.Valuer, writing the set as a json array of unique ids; empty sets are written as NULL
+func (kk KeySet) Value() (driver.Value, error) {
+	return setValue(IDSet(NewKeySet(kk.Strings()...)))
+}
+
+// Scan implements sql.Scanner, reading either a json array or a postgres array literal e.g. {a,b}
+func (kk *KeySet) Scan(src interface{}) error {
+	ss, err := scanSet(src)
+	if err != nil {
+		return err
+	}
+
+	*kk = NewKeySet(ss...)
+
+	return nil
+}
+
+func setValue(idSet IDSet) (driver.Value, error) {
+	if len(idSet) == 0 {
+		return nil, nil
+	}
+
+	data, err := json.Marshal(idSet.Strings())
+	if err != nil {
+		return nil, err
+	}
+
+	return string(data), nil
+}
+
+// scanString converts src into a string; ok is false if src is NULL
+func scanString(src interface{}) (s string, ok bool, err error) {
+	switch v := src.(type) {
+	case nil:
+		return "", false, nil
+	case string:
+		return v, true, nil
+	case []byte:
+		return string(v), true, nil
+	default:
+		return "", false, fmt.Errorf("unable to scan frn: unsupported type, %T", src)
+	}
+}
+
+// scanSet converts src, a json array or postgres array literal, into a string slice
+func scanSet(src interface{}) ([]string, error) {
+	s, ok, err := scanString(src)
+	if err != nil || !ok {
+		return nil, err
+	}
+
+	s = strings.TrimSpace(s)
+	switch {
+	case s == "":
+		return nil, nil
+	case strings.HasPrefix(s, "["):
+		var ss []string
+		if err := json.Unmarshal([]byte(s), &ss); err != nil {
+			return nil, fmt.Errorf("unable to scan frn set: %w", err)
+		}
+		return ss, nil
+	case strings.HasPrefix(s, "{") && strings.HasSuffix(s, "}"):
+		return parseArrayLiteral(s[1 : len(s)-1])
+	default:
+		return nil, fmt.Errorf("unable to scan frn set: expected json array or array literal")
+	}
+}
+
+// parseArrayLiteral parses the body of a postgres array literal e.g. a,"b",NULL
+func parseArrayLiteral(s string) ([]string, error) {
+	if s == "" {
+		return nil, nil
+	}
+
+	var ss []string
+	for s = strings.TrimSpace(s); len(s) > 0; {
+		var element string
+		if s[0] == '"' {
+			end := 1
+			for ; end < len(s) && s[end] != '"'; end++ {
+				if s[end] == '\\' {
+					end++
+				}
+			}
+			if end >= len(s) {
+				return nil, fmt.Errorf("unable to scan frn set: unterminated quote in array literal")
+			}
+
+			v, err := strconv.Unquote(s[:end+1])
+			if err != nil {
+				return nil, fmt.Errorf("unable to scan frn set: %w", err)
+			}
+			element, s = v, s[end+1:]
+		} else {
+			end := strings.Index(s, ",")
+			if end == -1 {
+				end = len(s)
+			}
+			element, s = strings.TrimSpace(s[:end]), s[end:]
+			if element == "NULL" {
+				element = ""
+			}
+		}
+
+		ss = append(ss, element)
+		s = strings.TrimSpace(strings.TrimPrefix(strings.TrimSpace(s), ","))
+	}
+
+	return ss, nil
+}
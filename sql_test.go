@@ -0,0 +1,154 @@
+package frn
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"io"
+	"testing"
+
+	"github.com/tj/assert"
+)
+
+// stubDriver is an in-process database/sql driver that echoes the values of the last Exec back from Query
+type stubDriver struct {
+	values []driver.Value
+}
+
+func (d *stubDriver) Open(string) (driver.Conn, error) { return stubConn{d: d}, nil }
+
+type stubConn struct{ d *stubDriver }
+
+func (c stubConn) Prepare(string) (driver.Stmt, error) { return stubStmt(c), nil }
+func (c stubConn) Close() error                        { return nil }
+func (c stubConn) Begin() (driver.Tx, error)           { return nil, errors.New("not supported") }
+
+type stubStmt struct{ d *stubDriver }
+
+func (s stubStmt) Close() error  { return nil }
+func (s stubStmt) NumInput() int { return -1 }
+
+func (s stubStmt) Exec(args []driver.Value) (driver.Result, error) {
+	s.d.values = args
+	return driver.RowsAffected(1), nil
+}
+
+func (s stubStmt) Query([]driver.Value) (driver.Rows, error) {
+	return &stubRows{values: s.d.values}, nil
+}
+
+type stubRows struct {
+	values []driver.Value
+	done   bool
+}
+
+func (r *stubRows) Columns() []string { return make([]string, len(r.values)) }
+func (r *stubRows) Close() error      { return nil }
+
+func (r *stubRows) Next(dest []driver.Value) error {
+	if r.done {
+		return io.EOF
+	}
+	copy(dest, r.values)
+	r.done = true
+	return nil
+}
+
+var stub = &stubDriver{}
+
+func init() {
+	sql.Register("frn-stub", stub)
+}
+
+func TestSQL_RoundTrip(t *testing.T) {
+	db, err := sql.Open("frn-stub", "")
+	assert.Nil(t, err)
+	defer db.Close()
+
+	var (
+		id      = ID("fm:crm:project:1")
+		idSet   = IDSet{"fm:crm:project:1", "", "fm:crm:project:2"}
+		keySet  = KeySet{"fm:crm:project:1", "fm:crm:project:1"}
+		emptyID ID
+	)
+	_, err = db.Exec("insert", id, Ptr(emptyID), idSet, keySet, IDSet{})
+	assert.Nil(t, err)
+	assert.Equal(t, []driver.Value{
+		"fm:crm:project:1",
+		nil,
+		`["fm:crm:project:1","fm:crm:project:2"]`,
+		`["fm:crm:project:1"]`,
+		nil,
+	}, stub.values)
+
+	var (
+		gotID      ID
+		gotEmptyID = ID("initial")
+		gotIDSet   IDSet
+		gotKeySet  KeySet
+		gotEmpty   = IDSet{"initial"}
+	)
+	err = db.QueryRow("select").Scan(&gotID, &gotEmptyID, &gotIDSet, &gotKeySet, &gotEmpty)
+	assert.Nil(t, err)
+	assert.Equal(t, id, gotID)
+	assert.Equal(t, emptyID, gotEmptyID)
+	assert.Equal(t, IDSet{"fm:crm:project:1", "fm:crm:project:2"}, gotIDSet)
+	assert.Equal(t, KeySet{"fm:crm:project:1"}, gotKeySet)
+	assert.Nil(t, gotEmpty)
+}
+
+func TestIDSet_Scan(t *testing.T) {
+	testCases := map[string]struct {
+		Src     interface{}
+		Want    IDSet
+		WantErr bool
+	}{
+		"nil": {
+			Src: nil,
+		},
+		"json": {
+			Src:  `["a","b"]`,
+			Want: IDSet{"a", "b"},
+		},
+		"json bytes": {
+			Src:  []byte(`["a"]`),
+			Want: IDSet{"a"},
+		},
+		"array literal": {
+			Src:  `{a,b}`,
+			Want: IDSet{"a", "b"},
+		},
+		"array literal quoted": {
+			Src:  `{"a", b ,NULL, "c\"d"}`,
+			Want: IDSet{"a", "b", `c"d`},
+		},
+		"empty array literal": {
+			Src: `{}`,
+		},
+		"unterminated": {
+			Src:     `{"a}`,
+			WantErr: true,
+		},
+		"garbage": {
+			Src:     `a,b`,
+			WantErr: true,
+		},
+		"unsupported": {
+			Src:     123,
+			WantErr: true,
+		},
+	}
+
+	for label, tc := range testCases {
+		t.Run(label, func(t *testing.T) {
+			var got IDSet
+			err := got.Scan(tc.Src)
+			if tc.WantErr {
+				assert.NotNil(t, err)
+				return
+			}
+			assert.Nil(t, err)
+			assert.Equal(t, tc.Want, got)
+		})
+	}
+}
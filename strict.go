@@ -0,0 +1,188 @@
+package frn
+
+import (
+	"database/sql/driver"
+)
+
+// StrictID is an ID whose json, text and sql decoding fails unless the id is blank or valid, so that handlers can
+// reject invalid ids at the boundary e.g. in a request body; convert to ID for use
+type StrictID ID
+
+// StrictIDSet is an IDSet whose json, text and sql decoding fails unless every id is blank or valid; see StrictID
+type StrictIDSet IDSet
+
+// StrictKeySet is a KeySet whose json, text and sql decoding fails unless every id is blank or valid; see StrictID
+type StrictKeySet KeySet
+
+// checkIDs returns the *ParseError of the first id that is neither blank nor valid
+func checkIDs(ids ...ID) error {
+	for _, id := range ids {
+		if id == "" {
+			continue
+		}
+		if _, err := Parse(id.String()); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (id StrictID) MarshalJSON() ([]byte, error) {
+	return ID(id).MarshalJSON()
+}
+
+func (id StrictID) MarshalText() ([]byte, error) {
+	return ID(id).MarshalText()
+}
+
+// UnmarshalJSON decodes a json string into the id; see ID.UnmarshalJSON
+func (id *StrictID) UnmarshalJSON(data []byte) error {
+	var v ID
+	if err := v.UnmarshalJSON(data); err != nil {
+		return err
+	}
+	if err := checkIDs(v); err != nil {
+		return err
+	}
+
+	*id = StrictID(v)
+
+	return nil
+}
+
+func (id *StrictID) UnmarshalText(data []byte) error {
+	var v ID
+	if err := v.UnmarshalText(data); err != nil {
+		return err
+	}
+	if err := checkIDs(v); err != nil {
+		return err
+	}
+
+	*id = StrictID(v)
+
+	return nil
+}
+
+// Scan implements sql.Scanner; see ID.Scan
+func (id *StrictID) Scan(src interface{}) error {
+	var v ID
+	if err := v.Scan(src); err != nil {
+		return err
+	}
+	if err := checkIDs(v); err != nil {
+		return err
+	}
+
+	*id = StrictID(v)
+
+	return nil
+}
+
+//goland:noinspection GoMixedReceiverTypes
+func (vv StrictIDSet) MarshalJSON() ([]byte, error) {
+	return IDSet(vv).MarshalJSON()
+}
+
+//goland:noinspection GoMixedReceiverTypes
+func (vv StrictIDSet) MarshalText() ([]byte, error) {
+	return IDSet(vv).MarshalText()
+}
+
+// Value implements driver.Valuer; see IDSet.Value
+//
+//goland:noinspection GoMixedReceiverTypes
+func (vv StrictIDSet) Value() (driver.Value, error) {
+	return IDSet(vv).Value()
+}
+
+// UnmarshalJSON decodes an array of ids into the set; see IDSet.UnmarshalJSON
+//
+//goland:noinspection GoMixedReceiverTypes
+func (vv *StrictIDSet) UnmarshalJSON(data []byte) error {
+	var v IDSet
+	if err := v.UnmarshalJSON(data); err != nil {
+		return err
+	}
+	return vv.set(v)
+}
+
+//goland:noinspection GoMixedReceiverTypes
+func (vv *StrictIDSet) UnmarshalText(data []byte) error {
+	var v IDSet
+	if err := v.UnmarshalText(data); err != nil {
+		return err
+	}
+	return vv.set(v)
+}
+
+// Scan implements sql.Scanner; see IDSet.Scan
+//
+//goland:noinspection GoMixedReceiverTypes
+func (vv *StrictIDSet) Scan(src interface{}) error {
+	var v IDSet
+	if err := v.Scan(src); err != nil {
+		return err
+	}
+	return vv.set(v)
+}
+
+//goland:noinspection GoMixedReceiverTypes
+func (vv *StrictIDSet) set(v IDSet) error {
+	if err := checkIDs(v...); err != nil {
+		return err
+	}
+
+	*vv = StrictIDSet(v)
+
+	return nil
+}
+
+func (kk StrictKeySet) MarshalJSON() ([]byte, error) {
+	return KeySet(kk).MarshalJSON()
+}
+
+func (kk StrictKeySet) MarshalText() ([]byte, error) {
+	return KeySet(kk).MarshalText()
+}
+
+// Value implements driver.Valuer; see KeySet.Value
+func (kk StrictKeySet) Value() (driver.Value, error) {
+	return KeySet(kk).Value()
+}
+
+// UnmarshalJSON decodes an array of ids into the set; see KeySet.UnmarshalJSON
+func (kk *StrictKeySet) UnmarshalJSON(data []byte) error {
+	var v KeySet
+	if err := v.UnmarshalJSON(data); err != nil {
+		return err
+	}
+	return kk.set(v)
+}
+
+func (kk *StrictKeySet) UnmarshalText(data []byte) error {
+	var v KeySet
+	if err := v.UnmarshalText(data); err != nil {
+		return err
+	}
+	return kk.set(v)
+}
+
+// Scan implements sql.Scanner; see KeySet.Scan
+func (kk *StrictKeySet) Scan(src interface{}) error {
+	var v KeySet
+	if err := v.Scan(src); err != nil {
+		return err
+	}
+	return kk.set(v)
+}
+
+func (kk *StrictKeySet) set(v KeySet) error {
+	if err := checkIDs(v...); err != nil {
+		return err
+	}
+
+	*kk = StrictKeySet(v)
+
+	return nil
+}
@@ -0,0 +1,112 @@
+package frn
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/tj/assert"
+)
+
+func TestStrictID(t *testing.T) {
+	testCases := map[string]struct {
+		Data       string
+		Want       StrictID
+		WantErrMsg string
+	}{
+		"ok": {
+			Data: `"fm:crm:project:1"`,
+			Want: "fm:crm:project:1",
+		},
+		"empty": {
+			Data: `""`,
+			Want: "",
+		},
+		"null": {
+			Data: `null`,
+			Want: "",
+		},
+		"invalid": {
+			Data:       `"blah"`,
+			WantErrMsg: `invalid frn, "blah": missing service at offset 4`,
+		},
+	}
+
+	for label, tc := range testCases {
+		t.Run(label, func(t *testing.T) {
+			got := StrictID("initial")
+			err := json.Unmarshal([]byte(tc.Data), &got)
+			if tc.WantErrMsg != "" {
+				assert.EqualError(t, err, tc.WantErrMsg)
+				assert.Equal(t, StrictID("initial"), got)
+				return
+			}
+			assert.Nil(t, err)
+			assert.Equal(t, tc.Want, got)
+
+			data, err := json.Marshal(got)
+			assert.Nil(t, err)
+			assert.Equal(t, `"`+string(tc.Want)+`"`, string(data))
+		})
+	}
+
+	var id StrictID
+	assert.NotNil(t, id.UnmarshalText([]byte("blah")))
+	assert.NotNil(t, id.Scan("blah"))
+	assert.Nil(t, id.Scan(nil))
+	assert.Nil(t, id.Scan([]byte("fm:crm:project:1")))
+	assert.Equal(t, StrictID("fm:crm:project:1"), id)
+
+	// decoding an ID is unaffected
+	var plain ID
+	assert.Nil(t, json.Unmarshal([]byte(`"blah"`), &plain))
+}
+
+func TestStrictSets(t *testing.T) {
+	testCases := map[string]struct {
+		Data       string
+		WantIDSet  StrictIDSet
+		WantKeySet StrictKeySet
+		WantErr    bool
+	}{
+		"ok": {
+			Data:       `["fm:crm:project:1","","fm:crm:project:1"]`,
+			WantIDSet:  StrictIDSet{"fm:crm:project:1", "fm:crm:project:1"},
+			WantKeySet: StrictKeySet{"fm:crm:project:1"},
+		},
+		"null": {
+			Data: `null`,
+		},
+		"invalid": {
+			Data:    `["fm:crm:project:1","blah"]`,
+			WantErr: true,
+		},
+	}
+
+	for label, tc := range testCases {
+		t.Run(label, func(t *testing.T) {
+			var idSet StrictIDSet
+			err := json.Unmarshal([]byte(tc.Data), &idSet)
+			assert.Equal(t, tc.WantErr, err != nil)
+			assert.Equal(t, tc.WantIDSet, idSet)
+
+			var keySet StrictKeySet
+			err = json.Unmarshal([]byte(tc.Data), &keySet)
+			assert.Equal(t, tc.WantErr, err != nil)
+			assert.Equal(t, tc.WantKeySet, keySet)
+		})
+	}
+
+	var idSet StrictIDSet
+	assert.NotNil(t, idSet.UnmarshalText([]byte("fm:crm:project:1,blah")))
+	assert.NotNil(t, idSet.Scan(`{fm:crm:project:1,blah}`))
+	assert.Nil(t, idSet.Scan(`{fm:crm:project:1,fm:crm:project:2}`))
+	assert.Equal(t, StrictIDSet{"fm:crm:project:1", "fm:crm:project:2"}, idSet)
+
+	value, err := idSet.Value()
+	assert.Nil(t, err)
+	assert.Equal(t, `["fm:crm:project:1","fm:crm:project:2"]`, value)
+
+	var keySet StrictKeySet
+	assert.NotNil(t, keySet.UnmarshalText([]byte("blah")))
+	assert.NotNil(t, keySet.Scan(`["blah"]`))
+}
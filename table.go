@@ -0,0 +1,84 @@
+package frn
+
+import (
+	"fmt"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+)
+
+// TableKey returns the single-table partition and sort keys of the id. The partition key is the immediate parent of
+// the id and the sort key is the id itself, so the children of an id and their paths share a partition e.g.
+// fm:crm:project:1:contract:2:approval:3/account => fm:crm:project:1:contract:2, fm:crm:project:1:contract:2:approval:3/account
+// An id without children is its own parent e.g. fm:crm:project:1/account => fm:crm:project:1, fm:crm:project:1/account
+func (id ID) TableKey() (pk, sk ID) {
+	if len(id.Lineage()) == 0 {
+		return "", ""
+	}
+	return id.Parent(), id
+}
+
+// FromTableKey rebuilds the id from its partition and sort keys, returning an error if the sort key is not a valid id
+// within the partition; see ID.TableKey
+func FromTableKey(pk, sk ID) (ID, error) {
+	if _, err := Parse(sk.String()); err != nil {
+		return "", err
+	}
+
+	within := sk.Base() == pk
+	if sk.HasChild() {
+		within = sk.ChildPrefix() == pk.String()+sep
+	}
+	if !within {
+		return "", fmt.Errorf("invalid frn table key, %q: sort key, %q, is not within partition", pk, sk)
+	}
+	return sk, nil
+}
+
+// Table describes the key attributes of a single-table DynamoDB table whose keys are derived via ID.TableKey
+type Table struct {
+	PartitionKey string // PartitionKey attribute name e.g. pk
+	SortKey      string // SortKey attribute name e.g. sk
+}
+
+// Key returns the key of the item for the id e.g. for GetItemInput.Key
+func (t Table) Key(id ID) map[string]*dynamodb.AttributeValue {
+	pk, sk := id.TableKey()
+	return map[string]*dynamodb.AttributeValue{
+		t.PartitionKey: {S: aws.String(pk.String())},
+		t.SortKey:      {S: aws.String(sk.String())},
+	}
+}
+
+// ChildrenOf returns a key condition for the immediate children of the id and their paths
+// e.g. fm:crm:project:1 => pk = fm:crm:project:1 AND begins_with fm:crm:project:1:
+func (t Table) ChildrenOf(id ID) Expression {
+	return t.beginsWith(id.Base(), id.Base().String()+sep)
+}
+
+// ChildrenOfType returns a key condition for the immediate children of the id of the type and their paths
+// e.g. fm:crm:project:1, contract => pk = fm:crm:project:1 AND begins_with fm:crm:project:1:contract:
+func (t Table) ChildrenOfType(id ID, ct Type) Expression {
+	return t.beginsWith(id.Base(), id.Base().String()+sep+ct.String()+sep)
+}
+
+// PathEntries returns a key condition for the path entries of the id with the head, which share the partition of the id
+// e.g. fm:fin:entity:1, account => pk = fm:fin:entity:1 AND begins_with fm:fin:entity:1/account/
+func (t Table) PathEntries(id ID, head string) Expression {
+	pk, _ := id.TableKey()
+	return t.beginsWith(pk, id.Base().String()+pathSep+EscapePath(head)+pathSep)
+}
+
+func (t Table) beginsWith(pk ID, prefix string) Expression {
+	return Expression{
+		Expression: "#frnpk = :frnpk AND begins_with(#frnsk, :frnsk)",
+		Names: map[string]*string{
+			"#frnpk": aws.String(t.PartitionKey),
+			"#frnsk": aws.String(t.SortKey),
+		},
+		Values: map[string]*dynamodb.AttributeValue{
+			":frnpk": {S: aws.String(pk.String())},
+			":frnsk": {S: aws.String(prefix)},
+		},
+	}
+}
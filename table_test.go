@@ -0,0 +1,126 @@
+package frn
+
+import (
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+	"github.com/tj/assert"
+)
+
+func TestID_TableKey(t *testing.T) {
+	testCases := map[string]struct {
+		ID     ID
+		WantPK ID
+		WantSK ID
+	}{
+		"root": {
+			ID:     "fm:crm:project:1",
+			WantPK: "fm:crm:project:1",
+			WantSK: "fm:crm:project:1",
+		},
+		"child": {
+			ID:     "fm:crm:project:1:contract:2",
+			WantPK: "fm:crm:project:1",
+			WantSK: "fm:crm:project:1:contract:2",
+		},
+		"root with path": {
+			ID:     "fm:crm:project:1/account/ar",
+			WantPK: "fm:crm:project:1",
+			WantSK: "fm:crm:project:1/account/ar",
+		},
+		"grandchild with path": {
+			ID:     "fm:crm:project:1:contract:2:approval:3/account/ar",
+			WantPK: "fm:crm:project:1:contract:2",
+			WantSK: "fm:crm:project:1:contract:2:approval:3/account/ar",
+		},
+		"empty": {},
+	}
+
+	for label, tc := range testCases {
+		t.Run(label, func(t *testing.T) {
+			pk, sk := tc.ID.TableKey()
+			assert.Equal(t, tc.WantPK, pk)
+			assert.Equal(t, tc.WantSK, sk)
+
+			got, err := FromTableKey(pk, sk)
+			if tc.ID == "" {
+				assert.NotNil(t, err)
+				return
+			}
+			assert.Nil(t, err)
+			assert.Equal(t, tc.ID, got)
+		})
+	}
+
+	for pk, sk := range map[ID]ID{
+		"fm:crm:project:1":            "fm:crm:project:2:contract:1",
+		"fm:crm:project:2":            "fm:crm:project:2:contract:1:approval:3",
+		"fm:crm:project:2:contract:1": "fm:crm:project:2",
+		"fm:crm:project":              "fm:crm:project:contract:1",
+	} {
+		_, err := FromTableKey(pk, sk)
+		assert.NotNil(t, err, sk)
+	}
+}
+
+func TestTable(t *testing.T) {
+	var (
+		table   = Table{PartitionKey: "pk", SortKey: "sk"}
+		project = ID("fm:crm:project:1")
+	)
+
+	testCases := map[string]struct {
+		Expression Expression
+		WantPK     string
+		WantPrefix string
+	}{
+		"children": {
+			Expression: table.ChildrenOf(project),
+			WantPK:     "fm:crm:project:1",
+			WantPrefix: "fm:crm:project:1:",
+		},
+		"children of nested": {
+			Expression: table.ChildrenOf("fm:crm:project:1:contract:2/account"),
+			WantPK:     "fm:crm:project:1:contract:2",
+			WantPrefix: "fm:crm:project:1:contract:2:",
+		},
+		"children of type of nested": {
+			Expression: table.ChildrenOfType("fm:crm:project:1:contract:2", TypeApproval),
+			WantPK:     "fm:crm:project:1:contract:2",
+			WantPrefix: "fm:crm:project:1:contract:2:approval:",
+		},
+		"children of type": {
+			Expression: table.ChildrenOfType(project, TypeContract),
+			WantPK:     "fm:crm:project:1",
+			WantPrefix: "fm:crm:project:1:contract:",
+		},
+		"path entries": {
+			Expression: table.PathEntries("fm:fin:entity:1", "account"),
+			WantPK:     "fm:fin:entity:1",
+			WantPrefix: "fm:fin:entity:1/account/",
+		},
+		"path entries of child": {
+			Expression: table.PathEntries("fm:fin:entity:1:fund:2", "account"),
+			WantPK:     "fm:fin:entity:1",
+			WantPrefix: "fm:fin:entity:1:fund:2/account/",
+		},
+	}
+
+	for label, tc := range testCases {
+		t.Run(label, func(t *testing.T) {
+			input := &dynamodb.QueryInput{}
+			tc.Expression.ApplyQuery(input)
+
+			assert.Equal(t, "#frnpk = :frnpk AND begins_with(#frnsk, :frnsk)", aws.StringValue(input.KeyConditionExpression))
+			assert.Equal(t, "pk", aws.StringValue(input.ExpressionAttributeNames["#frnpk"]))
+			assert.Equal(t, "sk", aws.StringValue(input.ExpressionAttributeNames["#frnsk"]))
+			assert.Equal(t, tc.WantPK, aws.StringValue(input.ExpressionAttributeValues[":frnpk"].S))
+			assert.Equal(t, tc.WantPrefix, aws.StringValue(input.ExpressionAttributeValues[":frnsk"].S))
+		})
+	}
+
+	key := table.Key("fm:crm:project:1:contract:2")
+	assert.Equal(t, "fm:crm:project:1", aws.StringValue(key["pk"].S))
+	assert.Equal(t, "fm:crm:project:1:contract:2", aws.StringValue(key["sk"].S))
+}
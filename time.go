@@ -0,0 +1,60 @@
+package frn
+
+import (
+	"bytes"
+	"encoding/binary"
+	"math"
+	"time"
+
+	"github.com/segmentio/ksuid"
+)
+
+// ksuidEpoch is the unix time, in seconds, from which KSUID timestamps are measured
+const ksuidEpoch = 1400000000
+
+// CreatedAt returns the creation time embedded in the KSUID value of the innermost segment of the id
+// e.g. fm:crm:project:1:contract:<ksuid> => time the contract was created. ok is false if the value is not a KSUID
+func (id ID) CreatedAt() (time.Time, bool) {
+	k, err := ksuid.Parse(id.components().leaf().Value)
+	if err != nil {
+		return time.Time{}, false
+	}
+	return k.Time(), true
+}
+
+// TimeRange returns the lowest and highest ids of the type whose KSUID values were created within [from, to].
+// KSUIDs have a resolution of one second, so ids created at any point during the second of to are included.
+// e.g. to query for ids created last week, use a DynamoDB key condition of BETWEEN lo AND hi
+func TimeRange(ns Namespace, t Type, from, to time.Time) (lo, hi ID) {
+	loValue, hiValue := TimeRangeValues(from, to)
+	return ns.New(t, loValue), ns.New(t, hiValue)
+}
+
+// ChildTimeRange is the same as TimeRange, but for children of the type nested directly within the id
+func (id ID) ChildTimeRange(t Type, from, to time.Time) (lo, hi ID) {
+	loValue, hiValue := TimeRangeValues(from, to)
+	return id.Sub(t, loValue), id.Sub(t, hiValue)
+}
+
+// TimeRangeValues returns the lowest and highest KSUID values created within [from, to]
+func TimeRangeValues(from, to time.Time) (lo, hi string) {
+	return ksuidBound(from, 0x00), ksuidBound(to, 0xff)
+}
+
+// ksuidBound returns the KSUID for the second containing t with every byte of the payload set to fill
+func ksuidBound(t time.Time, fill byte) string {
+	ts := t.Unix() - ksuidEpoch
+	switch {
+	case ts < 0:
+		ts = 0
+	case ts > math.MaxUint32:
+		ts = math.MaxUint32
+	}
+
+	data := make([]byte, 4, 20)
+	binary.BigEndian.PutUint32(data, uint32(ts))
+	data = append(data, bytes.Repeat([]byte{fill}, 16)...)
+
+	k, _ := ksuid.FromBytes(data)
+	return k.String()
+}
@@ -0,0 +1,92 @@
+package frn
+
+import (
+	"testing"
+	"time"
+
+	"github.com/segmentio/ksuid"
+	"github.com/tj/assert"
+)
+
+func TestID_CreatedAt(t *testing.T) {
+	var (
+		parentAt = time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+		childAt  = parentAt.Add(time.Hour)
+		parent   = ksuid.New()
+		child    = ksuid.New()
+	)
+	parent, _ = ksuid.FromParts(parentAt, parent.Payload())
+	child, _ = ksuid.FromParts(childAt, child.Payload())
+
+	testCases := map[string]struct {
+		ID     ID
+		Want   time.Time
+		WantOk bool
+	}{
+		"parent": {
+			ID:     NewNamespace("", ServiceCRM).New(TypeProject, parent.String()),
+			Want:   parentAt,
+			WantOk: true,
+		},
+		"child": {
+			ID:     NewNamespace("", ServiceCRM).NewWithChild(TypeProject, parent.String(), TypeEvent, child.String()),
+			Want:   childAt,
+			WantOk: true,
+		},
+		"child with path": {
+			ID:     NewNamespace("", ServiceCRM).NewWithChild(TypeProject, "1", TypeEvent, child.String()).WithPath("key", "value"),
+			Want:   childAt,
+			WantOk: true,
+		},
+		"not a ksuid": {
+			ID:     "fm:crm:project:1",
+			WantOk: false,
+		},
+		"empty": {
+			ID:     "",
+			WantOk: false,
+		},
+	}
+
+	for label, tc := range testCases {
+		t.Run(label, func(t *testing.T) {
+			got, ok := tc.ID.CreatedAt()
+			assert.Equal(t, tc.WantOk, ok)
+			assert.True(t, tc.Want.Equal(got), "want %v, got %v", tc.Want, got)
+		})
+	}
+}
+
+func TestTimeRange(t *testing.T) {
+	var (
+		ns   = NewNamespace("", ServiceCRM)
+		from = time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+		to   = from.Add(7 * 24 * time.Hour)
+	)
+	lo, hi := TimeRange(ns, TypeProject, from, to)
+
+	testCases := map[string]struct {
+		At   time.Time
+		Want bool
+	}{
+		"before":       {At: from.Add(-time.Second), Want: false},
+		"from":         {At: from, Want: true},
+		"within":       {At: from.Add(time.Hour), Want: true},
+		"to":           {At: to.Add(999 * time.Millisecond), Want: true},
+		"after":        {At: to.Add(time.Second), Want: false},
+		"before epoch": {At: time.Unix(0, 0), Want: false},
+	}
+
+	for label, tc := range testCases {
+		t.Run(label, func(t *testing.T) {
+			k, _ := ksuid.FromParts(tc.At, ksuid.New().Payload())
+			id := ns.New(TypeProject, k.String())
+			assert.Equal(t, tc.Want, lo <= id && id <= hi)
+		})
+	}
+
+	parent := ns.New(TypeProject, "1")
+	lo, hi = parent.ChildTimeRange(TypeEvent, from, to)
+	assert.Equal(t, parent.ChildPrefix()+TypeEvent.String(), lo.String()[:len(parent.ChildPrefix())+len(TypeEvent)])
+	assert.True(t, lo < hi)
+}
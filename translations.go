@@ -0,0 +1,153 @@
+package frn
+
+import (
+	"errors"
+	"strconv"
+
+	ut "github.com/go-playground/universal-translator"
+	"github.com/go-playground/validator/v10"
+)
+
+const (
+	keyInvalid    = "frn"
+	keyMissing    = "frn-missing"
+	keyUnexpected = "frn-unexpected"
+	keyMismatch   = "frn-mismatch"
+	keySchema     = "frn-schema"
+	keyPartPrefix = "frn-part-"
+
+	keyParseMissing   = "frn-parse-missing"
+	keyParseCharacter = "frn-parse-character"
+	keyParseEscape    = "frn-parse-escape"
+)
+
+// translations holds the messages for the frn tag by locale; {0} is the field, {1} the part, {2} and {3} the values.
+// Parse messages take the part of the id, the offending character, if any, and the offset.
+var translations = map[string]map[string]string{
+	"en": {
+		keyInvalid:                             "{0} must be a valid FRN",
+		keyMissing:                             "{0} is missing the {1}",
+		keyUnexpected:                          "{0} must not have a {1}, found {2}",
+		keyMismatch:                            "{0} has the wrong {1}: expected {2}, found {3}",
+		keySchema:                              "{0} must be an FRN permitted by the registry",
+		keyPartPrefix + string(PartEnv):        "env",
+		keyPartPrefix + string(PartService):    "service",
+		keyPartPrefix + string(PartParentType): "parent type",
+		keyPartPrefix + string(PartChild):      "child",
+		keyPartPrefix + string(PartChildType):  "child type",
+		keyPartPrefix + string(PartPath):       "path",
+		keyPartPrefix + string(PartPathHead):   "path head",
+		keyPartPrefix + string(PartPattern):    "shape",
+		keyPartPrefix + "type":                 "type",
+		keyPartPrefix + "value":                "value",
+		keyPartPrefix + "child value":          "child value",
+		keyParseMissing:                        "{0} must be a valid FRN: missing {1} at offset {2}",
+		keyParseCharacter:                      "{0} must be a valid FRN: invalid character {1} in {2} at offset {3}",
+		keyParseEscape:                         "{0} must be a valid FRN: invalid escape in {1} at offset {2}",
+	},
+	"es": {
+		keyInvalid:                             "{0} debe ser un FRN válido",
+		keyMissing:                             "a {0} le falta {1}",
+		keyUnexpected:                          "{0} no debe tener {1}, se encontró {2}",
+		keyMismatch:                            "{0} tiene {1} incorrecto: se esperaba {2}, se encontró {3}",
+		keySchema:                              "{0} debe ser un FRN permitido por el registro",
+		keyPartPrefix + string(PartEnv):        "entorno",
+		keyPartPrefix + string(PartService):    "servicio",
+		keyPartPrefix + string(PartParentType): "tipo padre",
+		keyPartPrefix + string(PartChild):      "hijo",
+		keyPartPrefix + string(PartChildType):  "tipo de hijo",
+		keyPartPrefix + string(PartPath):       "ruta",
+		keyPartPrefix + string(PartPathHead):   "encabezado de ruta",
+		keyPartPrefix + string(PartPattern):    "forma",
+		keyPartPrefix + "type":                 "tipo",
+		keyPartPrefix + "value":                "valor",
+		keyPartPrefix + "child value":          "valor de hijo",
+		keyParseMissing:                        "{0} debe ser un FRN válido: falta {1} en la posición {2}",
+		keyParseCharacter:                      "{0} debe ser un FRN válido: carácter {1} no válido en {2} en la posición {3}",
+		keyParseEscape:                         "{0} debe ser un FRN válido: escape no válido en {1} en la posición {2}",
+	},
+}
+
+// RegisterTranslations registers human-readable messages for the frn tag with the translator. Messages state which
+// part of the id mismatched and what was found; en and es are supported and other locales fall back to en.
+// Pass the options given to RegisterValidation so that ids rejected by the registry are reported as such.
+func RegisterTranslations(validate *validator.Validate, trans ut.Translator, opts ...ValidationOption) error {
+	messages, ok := translations[trans.Locale()]
+	if !ok {
+		messages = translations["en"]
+	}
+
+	register := func(trans ut.Translator) error {
+		for key, text := range messages {
+			if err := trans.Add(key, text, true); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	translate := func(trans ut.Translator, fe validator.FieldError) string {
+		return translateFieldError(trans, fe, opts...)
+	}
+	return validate.RegisterTranslation("frn", trans, register, translate)
+}
+
+func translateFieldError(trans ut.Translator, fe validator.FieldError, opts ...ValidationOption) string {
+	var (
+		text string
+		err  error
+		me   *MismatchError
+		pe   *ParseError
+		se   *SchemaError
+	)
+	explained := ExplainFieldError(fe, opts...)
+	switch {
+	case errors.As(explained, &me):
+		var part string
+		part, err = trans.T(keyPartPrefix + string(me.Part))
+		switch {
+		case err != nil:
+		case me.Got == "":
+			text, err = trans.T(keyMissing, fe.Field(), part)
+		case me.Want == "":
+			text, err = trans.T(keyUnexpected, fe.Field(), part, me.Got)
+		default:
+			text, err = trans.T(keyMismatch, fe.Field(), part, me.Want, me.Got)
+		}
+	case errors.As(explained, &pe):
+		text, err = translateParseError(trans, fe.Field(), pe)
+	case errors.As(explained, &se):
+		text, err = trans.T(keySchema, fe.Field())
+	default:
+		text, err = trans.T(keyInvalid, fe.Field())
+	}
+	if err != nil {
+		return fe.Error()
+	}
+
+	return text
+}
+
+// translateParseError translates the parse error by its code e.g. invalid character '.' in value
+func translateParseError(trans ut.Translator, field string, pe *ParseError) (string, error) {
+	if pe.Code == ParseEmpty || pe.Part == "" {
+		return trans.T(keyInvalid, field)
+	}
+
+	part, err := trans.T(keyPartPrefix + pe.Part)
+	if err != nil {
+		return "", err
+	}
+
+	offset := strconv.Itoa(pe.Offset)
+	switch pe.Code {
+	case ParseMissing:
+		return trans.T(keyParseMissing, field, part, offset)
+	case ParseCharacter:
+		return trans.T(keyParseCharacter, field, strconv.QuoteRune(rune(pe.Char)), part, offset)
+	case ParseEscape:
+		return trans.T(keyParseEscape, field, part, offset)
+	default:
+		return trans.T(keyInvalid, field)
+	}
+}
@@ -0,0 +1,127 @@
+package frn
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/go-playground/locales/en"
+	"github.com/go-playground/locales/es"
+	ut "github.com/go-playground/universal-translator"
+	"github.com/go-playground/validator/v10"
+	"github.com/tj/assert"
+)
+
+func TestRegisterTranslations(t *testing.T) {
+	type Example struct {
+		Value ID `validate:"frn=project/contract#account"`
+	}
+
+	testCases := map[string]struct {
+		Locale string
+		Value  ID
+		Want   string
+	}{
+		"en - invalid": {
+			Locale: "en",
+			Value:  "blah",
+			Want:   "Value must be a valid FRN: missing service at offset 4",
+		},
+		"en - invalid character": {
+			Locale: "en",
+			Value:  "fm:crm:project:1.2:contract:2/account/ar",
+			Want:   "Value must be a valid FRN: invalid character '.' in value at offset 16",
+		},
+		"es - invalid escape": {
+			Locale: "es",
+			Value:  "fm:crm:project:1:contract:%61/account/ar",
+			Want:   "Value debe ser un FRN válido: escape no válido en valor de hijo en la posición 26",
+		},
+		"en - parent": {
+			Locale: "en",
+			Value:  "fm:crm:entity:1:contract:2/account/ar",
+			Want:   "Value has the wrong parent type: expected project, found entity",
+		},
+		"en - missing child": {
+			Locale: "en",
+			Value:  "fm:crm:project:1/account/ar",
+			Want:   "Value is missing the child",
+		},
+		"en - path head": {
+			Locale: "en",
+			Value:  "fm:crm:project:1:contract:2/other/ar",
+			Want:   "Value has the wrong path head: expected account, found other",
+		},
+		"es - child type": {
+			Locale: "es",
+			Value:  "fm:crm:project:1:entity:2/account/ar",
+			Want:   "Value tiene tipo de hijo incorrecto: se esperaba contract, se encontró entity",
+		},
+		"es - missing path": {
+			Locale: "es",
+			Value:  "fm:crm:project:1:contract:2",
+			Want:   "a Value le falta ruta",
+		},
+	}
+
+	uni := ut.New(en.New(), en.New(), es.New())
+	for label, tc := range testCases {
+		t.Run(label, func(t *testing.T) {
+			trans, _ := uni.GetTranslator(tc.Locale)
+
+			validate := validator.New()
+			RegisterValidation(validate)
+			assert.Nil(t, RegisterTranslations(validate, trans))
+
+			err := validate.Struct(Example{Value: tc.Value})
+
+			var errs validator.ValidationErrors
+			assert.True(t, errors.As(err, &errs))
+			assert.Equal(t, tc.Want, errs[0].Translate(trans))
+		})
+	}
+}
+
+func TestRegisterTranslations_Invalid(t *testing.T) {
+	type Example struct {
+		Value ID `validate:"frn"`
+	}
+
+	trans, _ := ut.New(en.New(), en.New()).GetTranslator("en")
+
+	validate := validator.New()
+	RegisterValidation(validate)
+	assert.Nil(t, RegisterTranslations(validate, trans))
+
+	err := validate.Struct(Example{Value: "blah"})
+
+	var errs validator.ValidationErrors
+	assert.True(t, errors.As(err, &errs))
+	assert.Equal(t, "Value must be a valid FRN: missing service at offset 4", errs[0].Translate(trans))
+
+	var pe *ParseError
+	assert.True(t, errors.As(ExplainFieldError(errs[0]), &pe))
+}
+
+func TestRegisterTranslations_WithRegistry(t *testing.T) {
+	type Example struct {
+		Value ID `validate:"frn=contract"`
+	}
+
+	trans, _ := ut.New(en.New(), en.New()).GetTranslator("en")
+
+	validate := validator.New()
+	RegisterValidation(validate, WithRegistry(newTestRegistry()))
+	assert.Nil(t, RegisterTranslations(validate, trans, WithRegistry(newTestRegistry())))
+
+	// matches the pattern, but contracts may only be nested under projects
+	err := validate.Struct(Example{Value: "fm:crm:contract:1"})
+
+	var errs validator.ValidationErrors
+	assert.True(t, errors.As(err, &errs))
+	assert.Equal(t, "Value must be an FRN permitted by the registry", errs[0].Translate(trans))
+
+	var se *SchemaError
+	assert.True(t, errors.As(ExplainFieldError(errs[0], WithRegistry(newTestRegistry())), &se))
+	assert.Equal(t, "type, contract, must be nested under one of [project]", se.Reason)
+	assert.Nil(t, ExplainFieldError(errs[0]))
+}
@@ -0,0 +1,155 @@
+package frn
+
+import (
+	"database/sql/driver"
+	"fmt"
+	"sync"
+
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+)
+
+// Kind supplies the shape pattern a Typed id must match e.g.
+//
+//	type Project struct{}
+//
+//	func (Project) Shape() string { return "project" }
+type Kind interface {
+	Shape() string
+}
+
+// Typed is an id whose shape is checked against its Kind when constructed or decoded, so signatures such as
+// func Approve(p Typed[Project], c Typed[Contract]) are checked by the compiler. The zero value is an empty id.
+type Typed[K Kind] struct {
+	id ID
+}
+
+// kindPatterns caches the compiled Pattern for each Kind shape
+var kindPatterns sync.Map
+
+func kindPattern[K Kind]() (Pattern, error) {
+	var k K
+	shape := k.Shape()
+	if v, ok := kindPatterns.Load(shape); ok {
+		return v.(Pattern), nil
+	}
+	p, err := CompilePattern(shape)
+	if err != nil {
+		return Pattern{}, err
+	}
+	kindPatterns.Store(shape, p)
+	return p, nil
+}
+
+// NewTyped returns the id as a Typed id, or an error if the id is not set, is invalid or does not match the shape
+// of the Kind
+func NewTyped[K Kind](id ID) (Typed[K], error) {
+	if id == "" {
+		return Typed[K]{}, fmt.Errorf("ID not set")
+	}
+	if err := decodeTyped[K](id); err != nil {
+		return Typed[K]{}, err
+	}
+	return Typed[K]{id: id}, nil
+}
+
+// MustTyped is like NewTyped, but panics if the id does not match
+func MustTyped[K Kind](id ID) Typed[K] {
+	t, err := NewTyped[K](id)
+	if err != nil {
+		panic(err)
+	}
+	return t
+}
+
+// decodeTyped returns an error if the id is set and is invalid or does not match the shape of the Kind
+func decodeTyped[K Kind](id ID) error {
+	if id == "" {
+		return nil
+	}
+	if _, err := Parse(id.String()); err != nil {
+		return err
+	}
+	p, err := kindPattern[K]()
+	if err != nil {
+		return err
+	}
+	return p.Explain(id)
+}
+
+// ID returns the plain id
+func (t Typed[K]) ID() ID {
+	return t.id
+}
+
+func (t Typed[K]) IsEmpty() bool {
+	return t.id == ""
+}
+
+func (t Typed[K]) String() string {
+	return t.id.String()
+}
+
+func (t Typed[K]) MarshalJSON() ([]byte, error) {
+	return t.id.MarshalJSON()
+}
+
+func (t Typed[K]) MarshalText() ([]byte, error) {
+	return t.id.MarshalText()
+}
+
+// UnmarshalJSON decodes the id, returning an error if it does not match the shape of the Kind; null decodes to empty
+func (t *Typed[K]) UnmarshalJSON(data []byte) error {
+	var id ID
+	if err := id.UnmarshalJSON(data); err != nil {
+		return err
+	}
+	return t.set(id)
+}
+
+// UnmarshalText decodes the id, returning an error if it does not match the shape of the Kind
+func (t *Typed[K]) UnmarshalText(data []byte) error {
+	var id ID
+	if err := id.UnmarshalText(data); err != nil {
+		return err
+	}
+	return t.set(id)
+}
+
+// MarshalDynamoDBAttributeValue marshals the id as S or, if empty, NULL
+func (t Typed[K]) MarshalDynamoDBAttributeValue(item *dynamodb.AttributeValue) error {
+	return t.id.MarshalDynamoDBAttributeValue(item)
+}
+
+// UnmarshalDynamoDBAttributeValue unmarshals the id, returning an error if it does not match the shape of the Kind
+func (t *Typed[K]) UnmarshalDynamoDBAttributeValue(item *dynamodb.AttributeValue) error {
+	var id ID
+	if err := id.UnmarshalDynamoDBAttributeValue(item); err != nil {
+		return err
+	}
+	return t.set(id)
+}
+
+// Value implements driver.Valuer; an empty id is written as NULL
+func (t Typed[K]) Value() (driver.Value, error) {
+	if t.id == "" {
+		return nil, nil
+	}
+	return t.id.String(), nil
+}
+
+// Scan implements sql.Scanner, returning an error if the id does not match the shape of the Kind; NULL scans to empty
+func (t *Typed[K]) Scan(src interface{}) error {
+	var id ID
+	if err := id.Scan(src); err != nil {
+		return err
+	}
+	return t.set(id)
+}
+
+func (t *Typed[K]) set(id ID) error {
+	if err := decodeTyped[K](id); err != nil {
+		return err
+	}
+	t.id = id
+	return nil
+}
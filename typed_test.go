@@ -0,0 +1,138 @@
+package frn
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/service/dynamodb/dynamodbattribute"
+	"github.com/go-playground/validator/v10"
+	"github.com/tj/assert"
+)
+
+type kindProject struct{}
+
+func (kindProject) Shape() string { return "project" }
+
+type kindContract struct{}
+
+func (kindContract) Shape() string { return "project/contract" }
+
+func TestNewTyped(t *testing.T) {
+	testCases := map[string]struct {
+		ID      ID
+		WantErr bool
+	}{
+		"ok": {
+			ID: "fm:crm:project:1",
+		},
+		"empty": {
+			ID:      "",
+			WantErr: true,
+		},
+		"wrong shape": {
+			ID:      "fm:crm:project:1:contract:2",
+			WantErr: true,
+		},
+		"invalid": {
+			ID:      "fm:crm:project",
+			WantErr: true,
+		},
+	}
+
+	for label, tc := range testCases {
+		t.Run(label, func(t *testing.T) {
+			got, err := NewTyped[kindProject](tc.ID)
+			if tc.WantErr {
+				assert.NotNil(t, err)
+				assert.True(t, got.IsEmpty())
+				return
+			}
+			assert.Nil(t, err)
+			assert.Equal(t, tc.ID, got.ID())
+		})
+	}
+
+	assert.Panics(t, func() { MustTyped[kindContract]("fm:crm:project:1") })
+}
+
+func TestTyped_Marshal(t *testing.T) {
+	type Example struct {
+		Project  Typed[kindProject]
+		Contract Typed[kindContract]
+	}
+
+	want := Example{
+		Project:  MustTyped[kindProject]("fm:crm:project:1"),
+		Contract: MustTyped[kindContract]("fm:crm:project:1:contract:2"),
+	}
+
+	t.Run("json", func(t *testing.T) {
+		data, err := json.Marshal(want)
+		assert.Nil(t, err)
+		assert.Equal(t, `{"Project":"fm:crm:project:1","Contract":"fm:crm:project:1:contract:2"}`, string(data))
+
+		var got Example
+		assert.Nil(t, json.Unmarshal(data, &got))
+		assert.Equal(t, want, got)
+
+		assert.Nil(t, json.Unmarshal([]byte(`{"Project":null,"Contract":""}`), &got))
+		assert.Equal(t, Example{}, got)
+
+		err = json.Unmarshal([]byte(`{"Project":"fm:crm:contact:1"}`), &got)
+		var me *MismatchError
+		assert.True(t, errors.As(err, &me))
+		assert.Equal(t, PartParentType, me.Part)
+	})
+
+	t.Run("text", func(t *testing.T) {
+		var got map[Typed[kindProject]]int
+		assert.Nil(t, json.Unmarshal([]byte(`{"fm:crm:project:1":1}`), &got))
+		assert.Equal(t, 1, got[want.Project])
+	})
+
+	t.Run("dynamodb", func(t *testing.T) {
+		item, err := dynamodbattribute.MarshalMap(want)
+		assert.Nil(t, err)
+
+		var got Example
+		assert.Nil(t, dynamodbattribute.UnmarshalMap(item, &got))
+		assert.Equal(t, want, got)
+
+		item, err = dynamodbattribute.MarshalMap(Example{Project: want.Project})
+		assert.Nil(t, err)
+		assert.True(t, *item["Contract"].NULL)
+
+		item["Contract"] = item["Project"]
+		assert.NotNil(t, dynamodbattribute.UnmarshalMap(item, &got))
+	})
+
+	t.Run("sql", func(t *testing.T) {
+		v, err := want.Project.Value()
+		assert.Nil(t, err)
+		assert.Equal(t, "fm:crm:project:1", v)
+
+		v, err = Typed[kindProject]{}.Value()
+		assert.Nil(t, err)
+		assert.Nil(t, v)
+
+		var got Typed[kindProject]
+		assert.Nil(t, got.Scan([]byte("fm:crm:project:1")))
+		assert.Equal(t, want.Project, got)
+		assert.Nil(t, got.Scan(nil))
+		assert.True(t, got.IsEmpty())
+		assert.NotNil(t, got.Scan("fm:crm:project:1:contract:2"))
+	})
+}
+
+func TestTyped_Validator(t *testing.T) {
+	type Example struct {
+		Projects []Typed[kindProject] `validate:"frn=project"`
+	}
+
+	validate := validator.New()
+	RegisterValidation(validate)
+
+	assert.Nil(t, validate.Struct(Example{Projects: []Typed[kindProject]{MustTyped[kindProject]("fm:crm:project:1")}}))
+	assert.NotNil(t, validate.Struct(Example{Projects: []Typed[kindProject]{{id: "fm:crm:entity:1"}}}))
+}
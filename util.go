@@ -1,26 +1,38 @@
 package frn
 
 import (
-	"regexp"
 	"slices"
-
-	"github.com/segmentio/ksuid"
+	"strings"
 )
 
-var reShape = regexp.MustCompile(`^([a-zA-Z0-9_]+)(/([a-zA-Z0-9_]+))?(#([a-zA-Z0-9_]+))?`)
+// defaultGenerator generates values for NewValue
+var defaultGenerator = NewKSUIDGenerator()
 
 // NewValue generates a new value for an id
 func NewValue() string {
-	return ksuid.New().String()
+	return defaultGenerator.NewValue()
 }
 
 // SampleViaShape generates a sample id in the shape requested using the potential parent id as a base (if necessary)
+// Each path segment of the shape is kept e.g. entity#account/ar => fm:fin:entity:1/account/ar/_
 func SampleViaShape(ns Namespace, potentialParentID ID, s string) (ID, bool) {
-	shape := ShapeSlice(s)
-	return SampleViaShapeSlice(ns, potentialParentID, shape)
+	p, err := CompilePattern(s)
+	if err != nil {
+		p = Pattern{}
+	}
+	return sampleViaShape(ns, potentialParentID, p.Shape(), p.PathSegments())
 }
 
 func SampleViaShapeSlice(ns Namespace, potentialParentID ID, shape []string) (ID, bool) {
+	var path []string
+	if len(shape) == 3 && shape[2] != "" {
+		path = []string{shape[2]}
+	}
+	return sampleViaShape(ns, potentialParentID, shape, path)
+}
+
+// sampleViaShape is SampleViaShapeSlice with the path segments of the shape, the first being the path head
+func sampleViaShape(ns Namespace, potentialParentID ID, shape, path []string) (ID, bool) {
 	have := potentialParentID.ShapeSlice()
 	want := ParentShape(shape)
 	if !slices.Equal(have, want) {
@@ -31,9 +43,10 @@ func SampleViaShapeSlice(ns Namespace, potentialParentID ID, shape []string) (ID
 		for i := 2; i >= 0; i-- {
 			switch {
 			case i == 2 && shape[i] != "":
-				return potentialParentID.WithPath(shape[i], "_"), true
+				tail := append(append([]string(nil), path[1:]...), "_")
+				return potentialParentID.WithPath(path[0], tail...), true
 			case i == 1 && shape[i] != "":
-				return potentialParentID.Sub(Type(shape[i]), "_"), true
+				return potentialParentID.Sub(Type(lastChild(shape[i])), "_"), true
 			case i == 0:
 				return ns.New(Type(shape[i]), "_"), true
 			}
@@ -43,21 +56,22 @@ func SampleViaShapeSlice(ns Namespace, potentialParentID ID, shape []string) (ID
 	return "", false
 }
 
+// lastChild returns the innermost child of the children element of a shape slice e.g. contract/approval => approval
+func lastChild(children string) string {
+	if index := strings.LastIndex(children, "/"); index != -1 {
+		return children[index+1:]
+	}
+	return children
+}
+
 // ParentShape returns the logical parent shape for the given id
 func ParentShape(shape []string) []string {
-	parent := make([]string, 0, len(shape))
-	parent = append(parent, shape...)
-
 	if len(shape) == 3 {
-		for i := 2; i >= 0; i-- {
-			if parent[i] != "" {
-				parent[i] = ""
-				break
-			}
-		}
+		return patternFromShape(shape).Parent().Shape()
 	}
 
-	return parent
+	parent := make([]string, 0, len(shape))
+	return append(parent, shape...)
 }
 
 // ParentShapeString same as ParentShape, but for strings
@@ -67,14 +81,13 @@ func ParentShapeString(shape string) string {
 }
 
 // ShapeSlice takes a shape and returns a slice of 3 elements, one for each part (primary, secondary, and tertiary)
+// Nested children share the secondary element e.g. project/contract/approval => ["project", "contract/approval", ""]
 func ShapeSlice(shape string) []string {
-	ss := [3]string{}
-	if match := reShape.FindStringSubmatch(shape); len(match) == 6 {
-		ss[0] = match[1]
-		ss[1] = match[3]
-		ss[2] = match[5]
+	p, err := CompilePattern(shape)
+	if err != nil {
+		return make([]string, 3)
 	}
-	return ss[:]
+	return p.Shape()
 }
 
 // ShapeSliceValue transforms a shape slice back into a string
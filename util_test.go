@@ -6,41 +6,6 @@ import (
 	"github.com/tj/assert"
 )
 
-func TestReShape(t *testing.T) {
-	testCases := map[string]struct {
-		Shape string
-		Want  []string
-	}{
-		"empty": {
-			Shape: "",
-			Want:  nil,
-		},
-		"unary": {
-			Shape: "project",
-			Want:  []string{"project", "project", "", "", "", ""},
-		},
-		"binary": {
-			Shape: "project/contract",
-			Want:  []string{"project/contract", "project", "/contract", "contract", "", ""},
-		},
-		"tertiary": {
-			Shape: "project/contract#change",
-			Want:  []string{"project/contract#change", "project", "/contract", "contract", "#change", "change"},
-		},
-		"tertiary - alt": {
-			Shape: "project#change",
-			Want:  []string{"project#change", "project", "", "", "#change", "change"},
-		},
-	}
-
-	for label, tc := range testCases {
-		t.Run(label, func(t *testing.T) {
-			got := reShape.FindStringSubmatch(tc.Shape)
-			assert.Equal(t, tc.Want, got)
-		})
-	}
-}
-
 func TestParentShape(t *testing.T) {
 	testCases := map[string]struct {
 		Shape string
@@ -62,6 +27,10 @@ func TestParentShape(t *testing.T) {
 			Shape: "project#change",
 			Want:  []string{"project", "", ""},
 		},
+		"grandchild": {
+			Shape: "project/contract/approval",
+			Want:  []string{"project", "contract", ""},
+		},
 	}
 
 	for label, tc := range testCases {
@@ -91,12 +60,24 @@ func TestSampleViaShape(t *testing.T) {
 			Want:   "dev:crm:project:1:contract:2/work_item/_",
 			WantOk: true,
 		},
+		"path segments": {
+			ID:     "dev:fin:entity:1",
+			Shape:  "entity#account/ar",
+			Want:   "dev:fin:entity:1/account/ar/_",
+			WantOk: true,
+		},
 		"tertiary - alt": {
 			ID:     "dev:crm:project:1",
 			Shape:  "project#work_item",
 			Want:   "dev:crm:project:1/work_item/_",
 			WantOk: true,
 		},
+		"grandchild": {
+			ID:     "dev:crm:project:1:contract:2",
+			Shape:  "project/contract/approval",
+			Want:   "dev:crm:project:1:contract:2:approval:_",
+			WantOk: true,
+		},
 		"bad, tertiary": {
 			ID:     "dev:crm:project:1:entity:2",
 			Shape:  "project/contract#work_item",
@@ -119,6 +100,7 @@ func TestSampleViaShape(t *testing.T) {
 			}
 			assert.True(t, ok)
 			assert.Equal(t, tc.Want, got)
+			assert.Nil(t, Validate(got, tc.Shape))
 		})
 	}
 }
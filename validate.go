@@ -2,39 +2,80 @@ package frn
 
 import (
 	"fmt"
-	"regexp"
+	"reflect"
+	"sort"
 	"strings"
+	"sync"
 
 	"github.com/go-playground/validator/v10"
 )
 
-var re = regexp.MustCompile(`^([^/#]+)?(/)?([^/#]+)?(#)?([^#]+)?$`)
+type validationOptions struct {
+	registry    *Registry
+	strictTypes bool
+}
+
+// ValidationOption customizes the frn validation registered by RegisterValidation
+type ValidationOption func(*validationOptions)
+
+// WithRegistry requires ids to also conform to the schema described by the registry
+func WithRegistry(r *Registry) ValidationOption {
+	return func(o *validationOptions) {
+		o.registry = r
+	}
+}
+
+// WithStrictTypes fails validation of fields that cannot hold ids rather than passing them
+func WithStrictTypes() ValidationOption {
+	return func(o *validationOptions) {
+		o.strictTypes = true
+	}
+}
+
+// RegisterValidation registers the frn tag. Any field whose kind is string, or a pointer, slice, array or map whose
+// elements or keys are strings, may be validated e.g. ID, *ID, []*ID, [N]ID, IDSet, KeySet, map[ID]T. Elements
+// that are structs holding an id via an ID() ID method, such as Typed, may also be validated e.g. []Typed[Project]
+func RegisterValidation(validate *validator.Validate, opts ...ValidationOption) {
+	var options validationOptions
+	for _, opt := range opts {
+		opt(&options)
+	}
+
+	var patterns sync.Map // patterns caches the compiled Pattern for each tag param
+	compile := func(param string) (Pattern, error) {
+		if v, ok := patterns.Load(param); ok {
+			return v.(Pattern), nil
+		}
+		p, err := CompilePattern(param)
+		if err != nil {
+			return Pattern{}, err
+		}
+		patterns.Store(param, p)
+		return p, nil
+	}
 
-func RegisterValidation(validate *validator.Validate) {
 	fn := func(fl validator.FieldLevel) bool {
-		var ids []ID
-		switch v := fl.Field().Interface().(type) {
-		case ID:
-			ids = append(ids, v)
-		case []ID:
-			ids = append(ids, v...)
-		case IDSet:
-			ids = append(ids, v...)
-		case *ID:
-			if v == nil {
-				return true
-			}
-			ids = append(ids, *v)
-		default:
-			return true
+		ids, ok := collectIDs(nil, fl.Field())
+		if !ok {
+			return !options.strictTypes
 		}
 		if len(ids) == 0 {
 			return true
 		}
 
-		param := fl.Param()
+		pattern, err := compile(fl.Param())
+		if err != nil {
+			return false
+		}
+
 		for _, id := range ids {
-			if !isValidID(id, param) {
+			if id == "" {
+				continue
+			}
+			if !pattern.Match(id) {
+				return false
+			}
+			if options.registry != nil && options.registry.Check(id) != nil {
 				return false
 			}
 		}
@@ -48,71 +89,107 @@ func RegisterValidation(validate *validator.Validate) {
 	}
 }
 
-func Validate(id ID, patterns ...string) error {
-	if id == "" {
-		return fmt.Errorf("ID not set")
-	}
-
-	for _, pattern := range patterns {
-		if isValidID(id, pattern) {
-			return nil
+// collectIDs appends the ids held by v to ids; ok is false if v holds a kind that cannot be converted to ID
+func collectIDs(ids []ID, v reflect.Value) (_ []ID, ok bool) {
+	switch v.Kind() {
+	case reflect.Invalid:
+		return ids, true
+	case reflect.String:
+		return append(ids, ID(v.String())), true
+	case reflect.Ptr, reflect.Interface:
+		if v.IsNil() {
+			return ids, true
+		}
+		return collectIDs(ids, v.Elem())
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < v.Len(); i++ {
+			if ids, ok = collectIDs(ids, v.Index(i)); !ok {
+				return ids, false
+			}
+		}
+		return ids, true
+	case reflect.Struct:
+		if v.CanInterface() {
+			if holder, ok := v.Interface().(interface{ ID() ID }); ok {
+				return append(ids, holder.ID()), true
+			}
 		}
+		return ids, false
+	case reflect.Map:
+		if v.Type().Key().Kind() != reflect.String {
+			return ids, false
+		}
+		var keys []ID
+		for _, key := range v.MapKeys() {
+			keys = append(keys, ID(key.String()))
+		}
+		sort.Slice(keys, func(i, j int) bool { return keys[i] < keys[j] })
+		return append(ids, keys...), true
+	default:
+		return ids, false
 	}
-
-	return fmt.Errorf("ID invalid: expected on of %v", strings.Join(patterns, ", "))
 }
 
-func isValidID(id ID, pattern string) bool {
-	if id == "" {
-		return true
+// ExplainFieldError returns the *MismatchError or *ParseError describing why the field failed the frn tag or nil
+// if the failure cannot be explained by the tag's pattern e.g. the field failed a different tag. Pass the options
+// given to RegisterValidation to also explain failures against the registry with a *SchemaError; see WithRegistry
+func ExplainFieldError(fe validator.FieldError, opts ...ValidationOption) error {
+	if fe.Tag() != "frn" {
+		return nil
 	}
-	if pattern == "" {
-		return id.IsValid()
+
+	var options validationOptions
+	for _, opt := range opts {
+		opt(&options)
 	}
 
-	patternMatch := re.FindStringSubmatch(pattern)
-	if len(patternMatch) == 0 {
-		return false
+	p, err := CompilePattern(fe.Param())
+	if err != nil {
+		return err
 	}
 
-	// e.g. entity/card_tx#receipt
-	for index, value := range patternMatch {
-		switch index {
-		case 1: // entity
-			if value != "" {
-				if id.Type().String() != value {
-					return false // parent type mismatch
-				}
-			}
-		case 2: // /
-			switch {
-			case value == "" && id.HasChild():
-				return false
-			case value != "" && !id.HasChild():
-				return false
-			}
-		case 3: // card_tx
-			if value != "" {
-				if id.Child().Type().String() != value {
-					return false // child type mismatch
-				}
-			}
-		case 4: // #
-			switch {
-			case value == "" && id.HasPath():
-				return false
-			case value != "" && !id.HasPath():
-				return false
-			}
-		case 5: // receipt
-			if value != "" {
-				want, _, _ := id.Path()
-				if value != want {
-					return false
-				}
+	ids, _ := collectIDs(nil, reflect.ValueOf(fe.Value()))
+	for _, id := range ids {
+		if id == "" {
+			continue
+		}
+		if err := p.Explain(id); err != nil {
+			return err
+		}
+		if options.registry != nil {
+			if err := options.registry.Check(id); err != nil {
+				return err
 			}
 		}
 	}
 
-	return true
+	return nil
+}
+
+// Validate returns an error if the id is not set or matches none of the patterns. When a single pattern is
+// provided, the error is the *MismatchError returned by Pattern.Explain
+func Validate(id ID, patterns ...string) error {
+	if id == "" {
+		return fmt.Errorf("ID not set")
+	}
+
+	var first error
+	for _, pattern := range patterns {
+		p, err := CompilePattern(pattern)
+		if err != nil {
+			return err
+		}
+		err = p.Explain(id)
+		if err == nil {
+			return nil
+		}
+		if first == nil {
+			first = err
+		}
+	}
+
+	if len(patterns) == 1 {
+		return first
+	}
+	return &MismatchError{ID: id, Part: PartPattern, Want: strings.Join(patterns, ", "), Got: id.Shape()}
 }
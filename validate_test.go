@@ -1,6 +1,7 @@
 package frn
 
 import (
+	"errors"
 	"strings"
 	"testing"
 
@@ -24,6 +25,18 @@ func TestValidator(t *testing.T) {
 		assert.Nil(t, err)
 	})
 
+	t.Run("path segments", func(t *testing.T) {
+		type Example struct {
+			Value ID `validate:"frn=entity#account/ar"`
+		}
+
+		err := validate.Struct(Example{Value: "fm:fin:entity:1/account/ar/2024/q3"})
+		assert.Nil(t, err)
+
+		err = validate.Struct(Example{Value: "fm:fin:entity:1/account/ap/2024/q3"})
+		assert.NotNil(t, err)
+	})
+
 	t.Run("is frn", func(t *testing.T) {
 		type Example struct {
 			Value ID `validate:"frn"`
@@ -273,6 +286,84 @@ func TestValidator(t *testing.T) {
 		}
 	})
 
+	t.Run("grandchild", func(t *testing.T) {
+		type Example struct {
+			Value ID `validate:"frn=project/contract/approval"`
+		}
+
+		testCases := map[string]struct {
+			Value   ID
+			WantErr bool
+		}{
+			"ok": {
+				Value:   "fm:dev:project:1:contract:2:approval:3",
+				WantErr: false,
+			},
+			"child only": {
+				Value:   "fm:dev:project:1:contract:2",
+				WantErr: true,
+			},
+			"bad grandchild": {
+				Value:   "fm:dev:project:1:contract:2:other:3",
+				WantErr: true,
+			},
+			"too deep": {
+				Value:   "fm:dev:project:1:contract:2:approval:3:other:4",
+				WantErr: true,
+			},
+		}
+
+		for label, tc := range testCases {
+			t.Run(label, func(t *testing.T) {
+				err := validate.Struct(Example{Value: tc.Value})
+				if tc.WantErr {
+					assert.NotNil(t, err)
+				} else {
+					assert.Nil(t, err)
+				}
+			})
+		}
+	})
+
+	t.Run("alternation", func(t *testing.T) {
+		type Example struct {
+			Value ID `validate:"frn=crm:project0x7Ccontract"`
+		}
+
+		testCases := map[string]struct {
+			Value   ID
+			WantErr bool
+		}{
+			"project": {
+				Value:   "fm:crm:project:1",
+				WantErr: false,
+			},
+			"contract": {
+				Value:   "fm:crm:contract:1",
+				WantErr: false,
+			},
+			"wrong service": {
+				Value:   "fm:fin:project:1",
+				WantErr: true,
+			},
+			"wrong type": {
+				Value:   "fm:crm:entity:1",
+				WantErr: true,
+			},
+		}
+
+		for label, tc := range testCases {
+			t.Run(label, func(t *testing.T) {
+				err := validate.Struct(Example{Value: tc.Value})
+				if tc.WantErr {
+					assert.NotNil(t, err)
+				} else {
+					assert.Nil(t, err)
+				}
+			})
+		}
+	})
+
 	t.Run("no child", func(t *testing.T) {
 		type Example struct {
 			Value ID `validate:"frn=parent"`
@@ -468,6 +559,11 @@ func TestValidate(t *testing.T) {
 			Pattern: "#other",
 			WantErr: true,
 		},
+		"negation only - invalid": {
+			Value:   "blah",
+			Pattern: "!dev:*:**",
+			WantErr: true,
+		},
 	}
 
 	for label, tc := range testCases {
@@ -482,3 +578,105 @@ func TestValidate(t *testing.T) {
 		})
 	}
 }
+
+func TestValidate_MismatchError(t *testing.T) {
+	err := Validate("fm:dev:project:123:contract:456", "project/approval")
+
+	var me *MismatchError
+	assert.True(t, errors.As(err, &me))
+	assert.Equal(t, PartChildType, me.Part)
+	assert.Equal(t, "approval", me.Want)
+	assert.Equal(t, "contract", me.Got)
+
+	err = Validate("fm:dev:project:123", "contract", "entity")
+	assert.EqualError(t, err, "frn fm:dev:project:123: pattern mismatch: want contract, entity, got project")
+}
+
+func TestValidator_Kinds(t *testing.T) {
+	type ProjectID ID
+
+	type Example struct {
+		KeySet   KeySet           `validate:"frn=project"`
+		Ptrs     []*ID            `validate:"frn=project"`
+		Array    [2]ID            `validate:"frn=project"`
+		Map      map[ID]int       `validate:"frn=project"`
+		Named    ProjectID        `validate:"frn=project"`
+		NamedPtr *ProjectID       `validate:"frn=project"`
+		Nested   map[ID]*struct{} `validate:"frn=project"`
+	}
+
+	var (
+		project = ID("fm:crm:project:1")
+		entity  = ID("fm:crm:entity:1")
+		named   = ProjectID("fm:crm:project:2")
+	)
+
+	testCases := map[string]struct {
+		Value     Example
+		WantField string
+	}{
+		"ok": {
+			Value: Example{
+				KeySet:   KeySet{project},
+				Ptrs:     []*ID{&project, nil},
+				Array:    [2]ID{project, ""},
+				Map:      map[ID]int{project: 1},
+				Named:    named,
+				NamedPtr: &named,
+				Nested:   map[ID]*struct{}{project: nil},
+			},
+		},
+		"zero": {},
+		"KeySet": {
+			Value:     Example{KeySet: KeySet{project, entity}},
+			WantField: "KeySet",
+		},
+		"pointer slice": {
+			Value:     Example{Ptrs: []*ID{&entity}},
+			WantField: "Ptrs",
+		},
+		"array": {
+			Value:     Example{Array: [2]ID{project, entity}},
+			WantField: "Array",
+		},
+		"map keys": {
+			Value:     Example{Map: map[ID]int{entity: 1}},
+			WantField: "Map",
+		},
+		"named type": {
+			Value:     Example{Named: ProjectID(entity)},
+			WantField: "Named",
+		},
+	}
+
+	validate := validator.New()
+	RegisterValidation(validate)
+	for label, tc := range testCases {
+		t.Run(label, func(t *testing.T) {
+			err := validate.Struct(tc.Value)
+			if tc.WantField == "" {
+				assert.Nil(t, err)
+				return
+			}
+
+			var errs validator.ValidationErrors
+			assert.True(t, errors.As(err, &errs))
+			assert.Len(t, errs, 1)
+			assert.Equal(t, tc.WantField, errs[0].Field())
+		})
+	}
+}
+
+func TestValidator_WithStrictTypes(t *testing.T) {
+	type Example struct {
+		Value int `validate:"frn"`
+	}
+
+	validate := validator.New()
+	RegisterValidation(validate)
+	assert.Nil(t, validate.Struct(Example{Value: 1}))
+
+	validate = validator.New()
+	RegisterValidation(validate, WithStrictTypes())
+	assert.NotNil(t, validate.Struct(Example{Value: 1}))
+}